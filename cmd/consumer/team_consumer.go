@@ -39,8 +39,11 @@ func main() {
 	defer consumer.Close()
 
 	// Register event handlers
+	consumer.RegisterHandler(kafka.EventTeamCreated, createTeamCreatedHandler(teamCache))
+	consumer.RegisterHandler(kafka.EventTeamDeleted, createTeamDeletedHandler(teamCache))
 	consumer.RegisterHandler(kafka.EventMemberAdded, createMemberAddedHandler(teamCache))
 	consumer.RegisterHandler(kafka.EventMemberRemoved, createMemberRemovedHandler(teamCache))
+	// Role changes need no handler: the cache only tracks membership and Rosters stays the source of truth for roles
 
 	// Start consuming events
 	fmt.Println("Starting to consume team events...")
@@ -97,3 +100,46 @@ func createMemberRemovedHandler(teamCache *redisclient.TeamCache) func(kafka.Tea
 		return nil
 	}
 }
+
+func createTeamCreatedHandler(teamCache *redisclient.TeamCache) func(kafka.TeamEvent) error {
+	return func(event kafka.TeamEvent) error {
+		fmt.Printf("[%s] Team created: TeamID=%s, CreatedBy=%s\n",
+			event.Timestamp, event.TeamID, event.PerformedBy)
+
+		// Update Redis cache
+		if teamCache != nil {
+			ctx := context.Background()
+
+			// The creator is the main manager; other members arrive through MEMBER_ADDED
+			if err := teamCache.AddMember(ctx, event.TeamID, event.PerformedBy); err != nil {
+				log.Printf("Error updating Redis cache for team creation: %v", err)
+				return err
+			}
+
+			log.Printf("Successfully updated Redis cache: created team %s", event.TeamID)
+		}
+
+		return nil
+	}
+}
+
+func createTeamDeletedHandler(teamCache *redisclient.TeamCache) func(kafka.TeamEvent) error {
+	return func(event kafka.TeamEvent) error {
+		fmt.Printf("[%s] Team deleted: TeamID=%s, DeletedBy=%s\n",
+			event.Timestamp, event.TeamID, event.PerformedBy)
+
+		// Update Redis cache
+		if teamCache != nil {
+			ctx := context.Background()
+
+			if err := teamCache.DeleteTeam(ctx, event.TeamID); err != nil {
+				log.Printf("Error updating Redis cache for team deletion: %v", err)
+				return err
+			}
+
+			log.Printf("Successfully updated Redis cache: deleted team %s", event.TeamID)
+		}
+
+		return nil
+	}
+}
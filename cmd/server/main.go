@@ -45,6 +45,7 @@ func main() {
 		Protocol: 2, // Connection protocol
 	})
 	teamCache := redisclient.NewTeamCache(redis_client)
+	changeFeed := redisclient.NewChangeFeed(redis_client)
 
 	// Initialize Kafka producer
 	kafkaProducer, err := kafka.NewProducer(
@@ -62,7 +63,7 @@ func main() {
 	r := gin.Default()
 	middleware.SetupPrometheus(r)
 	r.Use(middleware.LoggerMiddleware())
-	router.SetupRouter(r, db, kafkaProducer, teamCache)
+	router.SetupRouter(r, db, kafkaProducer, teamCache, changeFeed)
 
 	port := os.Getenv("PORT")
 	if port == "" {
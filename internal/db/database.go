@@ -20,19 +20,106 @@ func CreateDSN() string {
 }
 
 func Connect(dsn string) (*gorm.DB, error) {
-	DB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// TranslateError turns unique violations into gorm.ErrDuplicatedKey
+	DB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{TranslateError: true})
 	// DB, err := pgx.Connect(context.Background(), os.Getenv("DATABASE_URL"))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	err = DB.AutoMigrate(&models.Team{}, &models.Roster{}, &models.Folder{}, &models.Note{}, &models.Share{})
+	// Per-user shares become user principals, and duplicates must be collapsed before AutoMigrate adds the unique index
+	if err := migrateSharePrincipals(DB); err != nil {
+		return nil, fmt.Errorf("failed to migrate share principals: %w", err)
+	}
+	if err := dedupeShares(DB); err != nil {
+		return nil, fmt.Errorf("failed to deduplicate shares: %w", err)
+	}
+	if err := dedupeRosters(DB); err != nil {
+		return nil, fmt.Errorf("failed to deduplicate rosters: %w", err)
+	}
+
+	err = DB.AutoMigrate(&models.Team{}, &models.Roster{}, &models.Folder{}, &models.Note{}, &models.Share{}, &models.Invitation{}, &models.NoteRevision{}, &models.Tag{}, &models.NoteTag{}, &models.FolderTag{}, &models.Attachment{}, &models.BlobDeletion{}, &models.Group{}, &models.GroupMember{}, &models.PublicLink{}, &models.NoteComment{}, &models.CommentMention{})
 
 	if err != nil {
 
 		return nil, fmt.Errorf("migration failed: %w", err)
 	}
 
+	// Full-text search index on notes; the expression must match repositories.noteSearchVector
+	err = DB.Exec(`CREATE INDEX IF NOT EXISTS idx_notes_search ON "Notes" USING GIN ` +
+		`(to_tsvector('english', coalesce(title, '') || ' ' || coalesce(content, '')))`).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to create search index: %w", err)
+	}
+
+	// A user has at most one pending invitation per team; older duplicates are declined first
+	err = DB.Exec(`
+		UPDATE "Invitations" i SET status = 'DECLINED' FROM (
+			SELECT id, row_number() OVER (PARTITION BY team_id, invitee_id ORDER BY created_at DESC, id) AS rn
+			FROM "Invitations" WHERE status = 'PENDING'
+		) ranked
+		WHERE i.id = ranked.id AND ranked.rn > 1`).Error
+	if err == nil {
+		err = DB.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_invitation_pending ON "Invitations" (team_id, invitee_id) ` +
+			`WHERE status = 'PENDING'`).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pending invitation index: %w", err)
+	}
+
 	return DB, nil
 }
+
+// migrateSharePrincipals renames Shares.user_id to principal_id and marks existing shares as user shares
+func migrateSharePrincipals(DB *gorm.DB) error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.Share{}) || !migrator.HasColumn(&models.Share{}, "user_id") {
+		return nil
+	}
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DROP INDEX IF EXISTS idx_share_resource_user`).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`ALTER TABLE "Shares" RENAME COLUMN user_id TO principal_id`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`ALTER TABLE "Shares" ADD COLUMN principal_type varchar(10) NOT NULL DEFAULT 'user'`).Error
+	})
+}
+
+// dedupeShares keeps one share per (resource, type, principal), preferring the strongest permission and then the
+// most recent grant. It only runs while the unique index on Shares does not exist yet.
+func dedupeShares(DB *gorm.DB) error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.Share{}) || migrator.HasIndex(&models.Share{}, "idx_share_principal") {
+		return nil
+	}
+	return DB.Exec(`
+		DELETE FROM "Shares" s USING (
+			SELECT id, row_number() OVER (
+				PARTITION BY resource_id, resource_type, principal_type, principal_id
+				ORDER BY (CASE permission WHEN 'manage' THEN 4 WHEN 'write' THEN 3 WHEN 'comment' THEN 2 ELSE 1 END) DESC, updated_at DESC, id
+			) AS rn
+			FROM "Shares"
+		) ranked
+		WHERE s.id = ranked.id AND ranked.rn > 1`).Error
+}
+
+// dedupeRosters keeps one roster row per (team, user), preferring the highest role. It only runs while the unique
+// index on Rosters does not exist yet.
+func dedupeRosters(DB *gorm.DB) error {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.Roster{}) || migrator.HasIndex(&models.Roster{}, "idx_roster_team_user") {
+		return nil
+	}
+	return DB.Exec(`
+		DELETE FROM "Rosters" r USING (
+			SELECT ctid, row_number() OVER (
+				PARTITION BY team_id, user_id
+				ORDER BY (CASE role WHEN 'MAIN_MANAGER' THEN 3 WHEN 'MANAGER' THEN 2 ELSE 1 END) DESC
+			) AS rn
+			FROM "Rosters"
+		) ranked
+		WHERE r.ctid = ranked.ctid AND ranked.rn > 1`).Error
+}
@@ -1,13 +1,27 @@
 package dto
 
+import (
+	"go_service/internal/models"
+	"go_service/pkg/textdiff"
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type CreateFolderRequest struct {
 	Name string `json:"name" binding:"required"`
 
-	TeamID string `json:"teamId" binding:"required"`
+	TeamID   string `json:"teamId" binding:"required"`
+	ParentID string `json:"parentId"`
 }
 
+type MoveFolderRequest struct {
+	ParentID *uuid.UUID `json:"parentId"` // null moves the folder to the top level
+}
+
+// UpdateFolderRequest replaces the folder's editable fields (PUT)
 type UpdateFolderRequest struct {
-	Name string `json:"name"`
+	Name string `json:"name" binding:"required,max=100"`
 }
 
 type CreateNoteRequest struct {
@@ -15,12 +29,155 @@ type CreateNoteRequest struct {
 	Content string `json:"content"`
 }
 
+// UpdateNoteRequest replaces the note's editable fields (PUT); an empty content clears it
 type UpdateNoteRequest struct {
-	Title   string `json:"title"`
+	Title   string `json:"title" binding:"required,max=255"`
 	Content string `json:"content"`
 }
 
+type MoveNoteRequest struct {
+	FolderID string `json:"folderId" binding:"required"`
+}
+
+type CopyNoteRequest struct {
+	FolderID string `json:"folderId" binding:"required"`
+	Title    string `json:"title"` // defaults to the source title
+}
+
+// ShareRequest grants access to a user, a team or a group; userId alone is shorthand for a user principal
 type ShareRequest struct {
-	UserID     string `json:"userId" binding:"required"`
-	Permission string `json:"permission" binding:"required,oneof=read write"`
+	UserID        string     `json:"userId" binding:"omitempty,uuid"`
+	PrincipalType string     `json:"principalType" binding:"omitempty,oneof=user team group"`
+	PrincipalID   string     `json:"principalId" binding:"omitempty,uuid"`
+	Permission    string     `json:"permission" binding:"required,oneof=read comment write manage"`
+	ExpiresAt     *time.Time `json:"expiresAt"` // optional end of access; omit for a permanent share
+}
+
+// UpdateShareRequest changes the permission of an existing share
+type UpdateShareRequest struct {
+	Permission string `json:"permission" binding:"required,oneof=read comment write manage"`
+}
+
+// TransferAssetRequest hands a folder or note over to another member of its team
+type TransferAssetRequest struct {
+	OwnerID uuid.UUID `json:"ownerId" binding:"required"`
+}
+
+// ShareResult reports whether sharing created a new share, changed its permission or left it as is
+type ShareResult struct {
+	Share  *models.Share `json:"share"`
+	Result string        `json:"result"` // "created", "updated" or "unchanged"
+}
+
+// ShareEntry is one grant in a folder or note's access list
+type ShareEntry struct {
+	PrincipalType string       `json:"principalType"`
+	PrincipalID   uuid.UUID    `json:"principalId"`
+	User          *models.User `json:"user,omitempty"` // resolved for user principals
+	Name          string       `json:"name,omitempty"` // team or group name
+	Permission    string       `json:"permission"`
+	InheritedFrom *uuid.UUID   `json:"inheritedFrom,omitempty"` // ancestor folder the share was granted on
+	ExpiresAt     *time.Time   `json:"expiresAt,omitempty"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	UpdatedAt     time.Time    `json:"updatedAt"`
+}
+
+// SharedWithMeRequest paginates the folders and notes shared with the caller
+type SharedWithMeRequest struct {
+	PageRequest
+	Type string `form:"type" binding:"omitempty,oneof=folder note"`
+}
+
+// SharedItem is a folder or note shared with the caller
+type SharedItem struct {
+	ID         uuid.UUID  `json:"id"`
+	Type       string     `json:"type"`
+	Name       string     `json:"name"`
+	TeamID     uuid.UUID  `json:"teamId"`
+	OwnerID    uuid.UUID  `json:"ownerId"`
+	Via        string     `json:"via"` // "user", "team" or "group"
+	Permission string     `json:"permission"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	SharedAt   time.Time  `json:"sharedAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// TeamAssets groups a user's folders by the team they belong to
+type TeamAssets struct {
+	TeamID  uuid.UUID       `json:"teamId"`
+	Folders []models.Folder `json:"folders"`
+}
+
+// FolderNode is one folder of a subtree returned by the tree endpoint
+type FolderNode struct {
+	models.Folder
+	Children []*FolderNode `json:"children"`
+}
+
+// RevisionDiff is a line diff between two revisions of a note
+type RevisionDiff struct {
+	NoteID   uuid.UUID       `json:"noteId"`
+	From     int             `json:"from"`
+	To       int             `json:"to"`
+	FromBy   uuid.UUID       `json:"fromAuthorId"`
+	ToBy     uuid.UUID       `json:"toAuthorId"`
+	FromDate time.Time       `json:"fromCreatedAt"`
+	ToDate   time.Time       `json:"toCreatedAt"`
+	Title    []textdiff.Line `json:"title"`
+	Content  []textdiff.Line `json:"content"`
+}
+
+// TrashResponse lists trashed items; content deleted with a folder is nested under it
+type TrashResponse struct {
+	Folders []models.Folder `json:"folders"`
+	Notes   []models.Note   `json:"notes"`
+}
+
+// SearchNotesRequest holds the query-string parameters of the note search endpoint
+type SearchNotesRequest struct {
+	Query       string    `form:"q" binding:"required"`
+	TeamID      string    `form:"teamId" binding:"omitempty,uuid"`
+	FolderID    string    `form:"folderId" binding:"omitempty,uuid"`
+	OwnerID     string    `form:"ownerId" binding:"omitempty,uuid"`
+	UpdatedFrom time.Time `form:"updatedFrom" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedTo   time.Time `form:"updatedTo" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit       int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor      string    `form:"cursor"`
+	Tags        string    `form:"tags"` // comma separated tag names
+	TagMatch    string    `form:"tagMatch" binding:"omitempty,oneof=all any"`
+}
+
+// NoteSearchHit is one ranked search result; highlights wrap matches in <mark> tags
+type NoteSearchHit struct {
+	ID             uuid.UUID `json:"id"`
+	Title          string    `json:"title"`
+	TitleHighlight string    `json:"titleHighlight"`
+	Snippet        string    `json:"snippet"`
+	FolderID       uuid.UUID `json:"folderId"`
+	OwnerID        uuid.UUID `json:"ownerId"`
+	TeamID         uuid.UUID `json:"teamId"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+	Rank           float64   `json:"rank"`
+}
+
+// NoteSearchResponse is a page of search results; pass NextCursor back to fetch the next page
+type NoteSearchResponse struct {
+	Results    []NoteSearchHit `json:"results"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+}
+
+type AttachTagRequest struct {
+	TagID string `json:"tagId" binding:"required,uuid"`
+}
+
+// TaggedAssetsRequest lists assets by tag names; match=all requires every tag, match=any (default) one of them
+type TaggedAssetsRequest struct {
+	Tags   string `form:"tags" binding:"required"`
+	Match  string `form:"match" binding:"omitempty,oneof=all any"`
+	TeamID string `form:"teamId" binding:"omitempty,uuid"`
+}
+
+type TaggedAssetsResponse struct {
+	Folders []models.Folder `json:"folders"`
+	Notes   []models.Note   `json:"notes"`
 }
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"go_service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// CreateCommentRequest starts a thread on a note, or replies to one when ParentID is set.
+// The body may @mention users by email address or user ID.
+type CreateCommentRequest struct {
+	Body     string     `json:"body" binding:"required,max=10000"`
+	ParentID *uuid.UUID `json:"parentId"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+// CommentListRequest filters the threads of a note by their resolved state
+type CommentListRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=open resolved"`
+}
+
+// CommentThread is a top-level comment with its replies, oldest first
+type CommentThread struct {
+	models.NoteComment
+	Replies []models.NoteComment `json:"replies"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+type CreateGroupRequest struct {
+	Name    string   `json:"name" binding:"required,max=100"`
+	UserIDs []string `json:"userIds" binding:"omitempty,dive,uuid"`
+}
+
+type UpdateGroupRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type GroupMembersRequest struct {
+	UserIDs []string `json:"userIds" binding:"required,min=1,dive,uuid"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// PageRequest holds the cursor pagination parameters shared by list endpoints
+type PageRequest struct {
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor string `form:"cursor"`
+}
+
+// Page is one page of a listing; pass NextCursor back as cursor to fetch the next page
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// AssetListRequest filters, sorts and paginates the manager asset listings
+type AssetListRequest struct {
+	PageRequest
+	Sort         string    `form:"sort" binding:"omitempty,oneof=name createdAt updatedAt"`
+	Order        string    `form:"order" binding:"omitempty,oneof=asc desc"`
+	OwnerID      string    `form:"ownerId" binding:"omitempty,uuid"`
+	UpdatedSince time.Time `form:"updatedSince" time_format:"2006-01-02T15:04:05Z07:00"`
+	NameContains string    `form:"nameContains"`
+	Notes        string    `form:"notes" binding:"omitempty,oneof=full summary count none"`
+}
@@ -0,0 +1,166 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MergePatchContentType is the media type of RFC 7396 JSON Merge Patch documents
+const MergePatchContentType = "application/merge-patch+json"
+
+// FieldErrors maps JSON field names to validation messages
+type FieldErrors map[string]string
+
+func (e FieldErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return "invalid fields: " + strings.Join(fields, ", ")
+}
+
+// NotePatch is a validated merge patch for a note; nil fields are left untouched
+type NotePatch struct {
+	Title   *string
+	Content *string // null in the patch clears the content
+}
+
+func (p *NotePatch) IsEmpty() bool {
+	return p.Title == nil && p.Content == nil
+}
+
+// FolderPatch is a validated merge patch for a folder; nil fields are left untouched
+type FolderPatch struct {
+	Name      *string
+	SetParent bool       // parentId was present in the patch
+	ParentID  *uuid.UUID // nil with SetParent moves the folder to the top level
+}
+
+func (p *FolderPatch) IsEmpty() bool {
+	return p.Name == nil && !p.SetParent
+}
+
+// Fields that exist on the resources but are managed by the server
+var readOnlyFields = map[string]bool{
+	"id": true, "ownerId": true, "teamId": true, "folderId": true, "version": true,
+	"createdAt": true, "updatedAt": true, "deletedAt": true, "deletedBy": true, "deletedWith": true,
+}
+
+// decodeMergePatch splits a merge patch into its members; RFC 7396 patches that are not objects are rejected
+func decodeMergePatch(raw []byte) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &members); err != nil || members == nil {
+		return nil, errors.New("merge patch must be a JSON object")
+	}
+	return members, nil
+}
+
+func isNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+// decodeText validates a required, non-null string member
+func decodeText(value json.RawMessage, maxLen int, errs FieldErrors, field string) *string {
+	if isNull(value) {
+		errs[field] = "must not be null"
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(value, &text); err != nil {
+		errs[field] = "must be a string"
+		return nil
+	}
+	if strings.TrimSpace(text) == "" {
+		errs[field] = "must not be empty"
+		return nil
+	}
+	if utf8.RuneCountInString(text) > maxLen {
+		errs[field] = "must be at most " + strconv.Itoa(maxLen) + " characters"
+		return nil
+	}
+	return &text
+}
+
+func rejectField(field string, errs FieldErrors) {
+	if readOnlyFields[field] {
+		errs[field] = "is read-only"
+	} else {
+		errs[field] = "unknown field"
+	}
+}
+
+// ParseNotePatch validates a merge patch for a note
+func ParseNotePatch(raw []byte) (*NotePatch, error) {
+	members, err := decodeMergePatch(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &NotePatch{}
+	errs := FieldErrors{}
+	for field, value := range members {
+		switch field {
+		case "title":
+			patch.Title = decodeText(value, 255, errs, field)
+		case "content":
+			content := ""
+			if !isNull(value) {
+				if err := json.Unmarshal(value, &content); err != nil {
+					errs[field] = "must be a string or null"
+					continue
+				}
+			}
+			patch.Content = &content
+		default:
+			rejectField(field, errs)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return patch, nil
+}
+
+// ParseFolderPatch validates a merge patch for a folder
+func ParseFolderPatch(raw []byte) (*FolderPatch, error) {
+	members, err := decodeMergePatch(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &FolderPatch{}
+	errs := FieldErrors{}
+	for field, value := range members {
+		switch field {
+		case "name":
+			patch.Name = decodeText(value, 100, errs, field)
+		case "parentId":
+			patch.SetParent = true
+			if isNull(value) {
+				continue
+			}
+			var parentID uuid.UUID
+			if err := json.Unmarshal(value, &parentID); err != nil {
+				errs[field] = "must be a UUID or null"
+				continue
+			}
+			patch.ParentID = &parentID
+		default:
+			rejectField(field, errs)
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return patch, nil
+}
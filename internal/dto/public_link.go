@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreatePublicLinkRequest mints an anonymous read-only link; both fields are optional
+type CreatePublicLinkRequest struct {
+	ExpiresAt *time.Time `json:"expiresAt"`
+	Password  string     `json:"password" binding:"omitempty,min=6,max=72"` // bcrypt ignores bytes past 72
+}
+
+// PublicNote is the read-only view of a note served through a public link
+type PublicNote struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PublicNoteSummary lists a note inside a public folder without its content
+type PublicNoteSummary struct {
+	ID        uuid.UUID `json:"id"`
+	Title     string    `json:"title"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PublicFolderSummary lists a sub-folder inside a public folder
+type PublicFolderSummary struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// PublicFolder is the read-only view of a folder served through a public link
+type PublicFolder struct {
+	ID      uuid.UUID             `json:"id"`
+	Name    string                `json:"name"`
+	Folders []PublicFolderSummary `json:"folders"`
+	Notes   []PublicNoteSummary   `json:"notes"`
+}
+
+// PublicLinkContent is what an anonymous visitor sees; exactly one of Note and Folder is set
+type PublicLinkContent struct {
+	ResourceType string        `json:"resourceType"`
+	ExpiresAt    *time.Time    `json:"expiresAt,omitempty"`
+	Note         *PublicNote   `json:"note,omitempty"`
+	Folder       *PublicFolder `json:"folder,omitempty"`
+}
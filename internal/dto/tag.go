@@ -0,0 +1,11 @@
+package dto
+
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+type UpdateTagRequest struct {
+	Name  string `json:"name" binding:"omitempty,max=50"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
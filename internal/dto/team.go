@@ -1,14 +1,45 @@
 package dto
 
 import (
+	"go_service/internal/models"
+
 	"github.com/google/uuid"
 )
 
 type CreateTeamReq struct {
 	TeamName string      `json:"teamName" binding:"required"`
-	UserIDs  []uuid.UUID `json:"userIds"`
+	UserIDs  []uuid.UUID `json:"userIds"` // rejected: members join through invitations
+}
+
+type AddManagerReq struct {
+	UserID uuid.UUID `json:"userId" binding:"required"`
+}
+
+type TransferOwnershipReq struct {
+	UserID uuid.UUID `json:"userId" binding:"required"`
+}
+
+type UpdateTeamReq struct {
+	TeamName string `json:"teamName" binding:"required"`
+}
+
+type InviteMemberReq struct {
+	UserID uuid.UUID `json:"userId"`
+	Email  string    `json:"email"`
+}
+
+// RemoveMemberReq is the offboarding policy for a removed member, read from the query string.
+// By default the member's folders and notes go to the MAIN_MANAGER and their direct shares are revoked.
+type RemoveMemberReq struct {
+	Assets     string `form:"assets" binding:"omitempty,oneof=reassign keep"`
+	ReassignTo string `form:"reassignTo" binding:"omitempty,uuid"` // a manager of the team
+	Shares     string `form:"shares" binding:"omitempty,oneof=revoke keep"`
 }
 
-type AddMemberReq struct {
-	UserIDs []uuid.UUID `json:"userIds" binding:"required,min=1"`
+// OffboardingReport lists what removing a member changed besides the roster
+type OffboardingReport struct {
+	ReassignedTo      *uuid.UUID     `json:"reassignedTo,omitempty"`
+	ReassignedFolders []uuid.UUID    `json:"reassignedFolders"`
+	ReassignedNotes   []uuid.UUID    `json:"reassignedNotes"`
+	RevokedShares     []models.Share `json:"revokedShares"`
 }
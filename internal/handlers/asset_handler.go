@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"fmt"
 	"go_service/internal/dto"
 	"go_service/internal/services"
 	"go_service/pkg/responses"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -47,6 +49,7 @@ func (h *AssetHandler) GetFolder(c *gin.Context) {
 		responses.Error(c, http.StatusNotFound, err, "Folder not found or access denied")
 		return
 	}
+	setETag(c, folder.Version)
 	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": folder})
 }
 
@@ -61,12 +64,54 @@ func (h *AssetHandler) UpdateFolder(c *gin.Context) {
 		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
 		return
 	}
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid If-Match header")
+		return
+	}
 	userID, _ := c.Get("user_id")
-	folder, err := h.service.UpdateFolder(c.Request.Context(), folderID, userID.(uuid.UUID), &req)
+	folder, err := h.service.UpdateFolder(c.Request.Context(), folderID, userID.(uuid.UUID), &req, expectedVersion)
 	if err != nil {
+		if respondVersionConflict(c, err) {
+			return
+		}
 		responses.Error(c, http.StatusForbidden, err, "Update failed or access denied")
 		return
 	}
+	setETag(c, folder.Version)
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": folder})
+}
+
+func (h *AssetHandler) PatchFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	raw, ok := readMergePatch(c)
+	if !ok {
+		return
+	}
+	patch, err := dto.ParseFolderPatch(raw)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid If-Match header")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	folder, err := h.service.PatchFolder(c.Request.Context(), folderID, userID.(uuid.UUID), patch, expectedVersion)
+	if err != nil {
+		if respondVersionConflict(c, err) {
+			return
+		}
+		responses.Error(c, http.StatusForbidden, err, "Update failed or access denied")
+		return
+	}
+	setETag(c, folder.Version)
 	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": folder})
 }
 
@@ -85,6 +130,70 @@ func (h *AssetHandler) DeleteFolder(c *gin.Context) {
 	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Folder deleted"})
 }
 
+func (h *AssetHandler) GetFolderTree(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	depth := 0
+	if depthStr := c.Query("depth"); depthStr != "" {
+		depth, err = strconv.Atoi(depthStr)
+		if err != nil || depth < 1 {
+			responses.Error(c, http.StatusBadRequest, fmt.Errorf("depth must be a positive integer"), "Invalid depth")
+			return
+		}
+	}
+	userID, _ := c.Get("user_id")
+	tree, err := h.service.GetFolderTree(c.Request.Context(), folderID, userID.(uuid.UUID), depth)
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Folder not found or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": tree})
+}
+
+func (h *AssetHandler) MoveFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	var req dto.MoveFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	folder, err := h.service.MoveFolder(c.Request.Context(), folderID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Move folder failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": folder})
+}
+
+// PUT /assets/folders/:folderId/owner (owner or team manager)
+func (h *AssetHandler) TransferFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	var req dto.TransferAssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	folder, err := h.service.TransferFolder(c.Request.Context(), folderID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Transfer folder failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": folder})
+}
+
 // Note Handlers
 func (h *AssetHandler) CreateNote(c *gin.Context) {
 	folderID, err := uuid.Parse(c.Param("folderId"))
@@ -118,6 +227,7 @@ func (h *AssetHandler) GetNote(c *gin.Context) {
 		responses.Error(c, http.StatusNotFound, err, "Note not found or access denied")
 		return
 	}
+	setETag(c, note.Version)
 	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": note})
 }
 
@@ -132,12 +242,54 @@ func (h *AssetHandler) UpdateNote(c *gin.Context) {
 		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
 		return
 	}
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid If-Match header")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	note, err := h.service.UpdateNote(c.Request.Context(), noteID, userID.(uuid.UUID), &req, expectedVersion)
+	if err != nil {
+		if respondVersionConflict(c, err) {
+			return
+		}
+		responses.Error(c, http.StatusForbidden, err, "Update note failed or access denied")
+		return
+	}
+	setETag(c, note.Version)
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": note})
+}
+
+func (h *AssetHandler) PatchNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	raw, ok := readMergePatch(c)
+	if !ok {
+		return
+	}
+	patch, err := dto.ParseNotePatch(raw)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid If-Match header")
+		return
+	}
 	userID, _ := c.Get("user_id")
-	note, err := h.service.UpdateNote(c.Request.Context(), noteID, userID.(uuid.UUID), &req)
+	note, err := h.service.PatchNote(c.Request.Context(), noteID, userID.(uuid.UUID), patch, expectedVersion)
 	if err != nil {
+		if respondVersionConflict(c, err) {
+			return
+		}
 		responses.Error(c, http.StatusForbidden, err, "Update note failed or access denied")
 		return
 	}
+	setETag(c, note.Version)
 	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": note})
 }
 
@@ -156,6 +308,148 @@ func (h *AssetHandler) DeleteNote(c *gin.Context) {
 	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Note deleted"})
 }
 
+func (h *AssetHandler) MoveNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	var req dto.MoveNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	note, err := h.service.MoveNote(c.Request.Context(), noteID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Move note failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": note})
+}
+
+func (h *AssetHandler) CopyNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	var req dto.CopyNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	note, err := h.service.CopyNote(c.Request.Context(), noteID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Copy note failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusCreated, gin.H{"success": true, "data": note})
+}
+
+// PUT /assets/notes/:noteId/owner (owner or team manager)
+func (h *AssetHandler) TransferNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	var req dto.TransferAssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	note, err := h.service.TransferNote(c.Request.Context(), noteID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Transfer note failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": note})
+}
+
+// Revision Handlers
+func (h *AssetHandler) GetNoteRevisions(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	revisions, err := h.service.GetNoteRevisions(c.Request.Context(), noteID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Note not found or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": revisions})
+}
+
+func (h *AssetHandler) GetNoteRevision(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid revision number")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	noteRevision, err := h.service.GetNoteRevision(c.Request.Context(), noteID, userID.(uuid.UUID), revision)
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Revision not found or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": noteRevision})
+}
+
+func (h *AssetHandler) DiffNoteRevisions(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	from, err := strconv.Atoi(c.Query("from"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid 'from' revision number")
+		return
+	}
+	to, err := strconv.Atoi(c.Query("to"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid 'to' revision number")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	diff, err := h.service.DiffNoteRevisions(c.Request.Context(), noteID, userID.(uuid.UUID), from, to)
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Revision not found or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": diff})
+}
+
+func (h *AssetHandler) RestoreNoteRevision(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid revision number")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	note, err := h.service.RestoreNoteRevision(c.Request.Context(), noteID, userID.(uuid.UUID), revision)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Restore revision failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": note})
+}
+
 // Sharing Handlers
 func (h *AssetHandler) ShareFolder(c *gin.Context) {
 	folderID, err := uuid.Parse(c.Param("folderId"))
@@ -169,12 +463,37 @@ func (h *AssetHandler) ShareFolder(c *gin.Context) {
 		return
 	}
 	userID, _ := c.Get("user_id")
-	err = h.service.ShareResource(c.Request.Context(), folderID, userID.(uuid.UUID), "folder", &req)
+	result, err := h.service.ShareResource(c.Request.Context(), folderID, userID.(uuid.UUID), "folder", &req)
 	if err != nil {
 		responses.Error(c, http.StatusForbidden, err, "Share folder failed or access denied")
 		return
 	}
-	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Folder shared"})
+	responses.JSON(c, shareStatusCode(result), gin.H{"success": true, "message": "Folder shared", "data": result})
+}
+
+func (h *AssetHandler) UpdateFolderShare(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	principalType, principalID, err := sharePrincipalParam(c)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid share principal")
+		return
+	}
+	var req dto.UpdateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	share, err := h.service.UpdateSharePermission(c.Request.Context(), folderID, userID.(uuid.UUID), "folder", principalType, principalID, &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Update folder share failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": share})
 }
 
 func (h *AssetHandler) RevokeFolderShare(c *gin.Context) {
@@ -183,13 +502,13 @@ func (h *AssetHandler) RevokeFolderShare(c *gin.Context) {
 		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
 		return
 	}
-	targetUserID, err := uuid.Parse(c.Param("userId"))
+	principalType, principalID, err := sharePrincipalParam(c)
 	if err != nil {
-		responses.Error(c, http.StatusBadRequest, err, "Invalid user ID format")
+		responses.Error(c, http.StatusBadRequest, err, "Invalid share principal")
 		return
 	}
 	userID, _ := c.Get("user_id")
-	err = h.service.RevokeShare(c.Request.Context(), folderID, userID.(uuid.UUID), targetUserID, "folder")
+	err = h.service.RevokeShare(c.Request.Context(), folderID, userID.(uuid.UUID), "folder", principalType, principalID)
 	if err != nil {
 		responses.Error(c, http.StatusForbidden, err, "Revoke folder share failed or access denied")
 		return
@@ -209,12 +528,37 @@ func (h *AssetHandler) ShareNote(c *gin.Context) {
 		return
 	}
 	userID, _ := c.Get("user_id")
-	err = h.service.ShareResource(c.Request.Context(), noteID, userID.(uuid.UUID), "note", &req)
+	result, err := h.service.ShareResource(c.Request.Context(), noteID, userID.(uuid.UUID), "note", &req)
 	if err != nil {
 		responses.Error(c, http.StatusForbidden, err, "Share note failed or access denied")
 		return
 	}
-	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Note shared"})
+	responses.JSON(c, shareStatusCode(result), gin.H{"success": true, "message": "Note shared", "data": result})
+}
+
+func (h *AssetHandler) UpdateNoteShare(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	principalType, principalID, err := sharePrincipalParam(c)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid share principal")
+		return
+	}
+	var req dto.UpdateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	share, err := h.service.UpdateSharePermission(c.Request.Context(), noteID, userID.(uuid.UUID), "note", principalType, principalID, &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Update note share failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": share})
 }
 
 func (h *AssetHandler) RevokeNoteShare(c *gin.Context) {
@@ -223,13 +567,13 @@ func (h *AssetHandler) RevokeNoteShare(c *gin.Context) {
 		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
 		return
 	}
-	targetUserID, err := uuid.Parse(c.Param("userId"))
+	principalType, principalID, err := sharePrincipalParam(c)
 	if err != nil {
-		responses.Error(c, http.StatusBadRequest, err, "Invalid user ID format")
+		responses.Error(c, http.StatusBadRequest, err, "Invalid share principal")
 		return
 	}
 	userID, _ := c.Get("user_id")
-	err = h.service.RevokeShare(c.Request.Context(), noteID, userID.(uuid.UUID), targetUserID, "note")
+	err = h.service.RevokeShare(c.Request.Context(), noteID, userID.(uuid.UUID), "note", principalType, principalID)
 	if err != nil {
 		responses.Error(c, http.StatusForbidden, err, "Revoke note share failed or access denied")
 		return
@@ -237,15 +581,282 @@ func (h *AssetHandler) RevokeNoteShare(c *gin.Context) {
 	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Note share revoked"})
 }
 
+func (h *AssetHandler) GetFolderShares(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	shares, err := h.service.GetResourceShares(c.Request.Context(), folderID, userID.(uuid.UUID), "folder")
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Get folder shares failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": shares})
+}
+
+func (h *AssetHandler) GetNoteShares(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	shares, err := h.service.GetResourceShares(c.Request.Context(), noteID, userID.(uuid.UUID), "note")
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Get note shares failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": shares})
+}
+
+func (h *AssetHandler) GetSharedWithMe(c *gin.Context) {
+	var req dto.SharedWithMeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid list parameters")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	shared, err := h.service.GetSharedWithMe(c.Request.Context(), userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Get shared items failed")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": shared})
+}
+
+// sharePrincipalParam reads the :principalId of a share route and its principalType query (user by default)
+func sharePrincipalParam(c *gin.Context) (string, uuid.UUID, error) {
+	principalType := c.DefaultQuery("principalType", "user")
+	if principalType != "user" && principalType != "team" && principalType != "group" {
+		return "", uuid.Nil, fmt.Errorf("unknown principal type %q", principalType)
+	}
+	principalID, err := uuid.Parse(c.Param("principalId"))
+	if err != nil {
+		return "", uuid.Nil, err
+	}
+	return principalType, principalID, nil
+}
+
+// shareStatusCode answers 201 when sharing created a new share and 200 when it updated or kept one
+func shareStatusCode(result *dto.ShareResult) int {
+	if result.Result == "created" {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+// Search Handlers
+func (h *AssetHandler) SearchNotes(c *gin.Context) {
+	var req dto.SearchNotesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid search parameters")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	results, err := h.service.SearchNotes(c.Request.Context(), userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Search failed")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": results})
+}
+
+func (h *AssetHandler) GetTaggedAssets(c *gin.Context) {
+	var req dto.TaggedAssetsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid tag filter")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	assets, err := h.service.GetTaggedAssets(c.Request.Context(), userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Failed to list tagged assets")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": assets})
+}
+
+// Tag Handlers
+func (h *AssetHandler) GetNoteTags(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	tags, err := h.service.GetNoteTags(c.Request.Context(), noteID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Note not found or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+func (h *AssetHandler) AttachNoteTag(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	var req dto.AttachTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	tags, err := h.service.AttachNoteTag(c.Request.Context(), noteID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Tag note failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+func (h *AssetHandler) DetachNoteTag(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	tagID, err := uuid.Parse(c.Param("tagId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid tag ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	if err := h.service.DetachNoteTag(c.Request.Context(), noteID, tagID, userID.(uuid.UUID)); err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Untag note failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Tag removed from note"})
+}
+
+func (h *AssetHandler) GetFolderTags(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	tags, err := h.service.GetFolderTags(c.Request.Context(), folderID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Folder not found or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+func (h *AssetHandler) AttachFolderTag(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	var req dto.AttachTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	tags, err := h.service.AttachFolderTag(c.Request.Context(), folderID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Tag folder failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+func (h *AssetHandler) DetachFolderTag(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	tagID, err := uuid.Parse(c.Param("tagId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid tag ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	if err := h.service.DetachFolderTag(c.Request.Context(), folderID, tagID, userID.(uuid.UUID)); err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Untag folder failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Tag removed from folder"})
+}
+
+// Trash Handlers
+func (h *AssetHandler) GetMyTrash(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	trash, err := h.service.GetMyTrash(c.Request.Context(), userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to retrieve trash")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": trash})
+}
+
+func (h *AssetHandler) RestoreFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	folder, err := h.service.RestoreFolder(c.Request.Context(), folderID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Restore folder failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": folder})
+}
+
+func (h *AssetHandler) RestoreNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	note, err := h.service.RestoreNote(c.Request.Context(), noteID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Restore note failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": note})
+}
+
 // Manager-only APIs
+func (h *AssetHandler) GetTeamTrash(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+	userID, _ := c.Get("user_id")
+	trash, err := h.service.GetTeamTrash(c.Request.Context(), teamID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Get team trash failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": trash})
+}
+
 func (h *AssetHandler) GetTeamAssets(c *gin.Context) {
 	teamID, err := uuid.Parse(c.Param("teamId"))
 	if err != nil {
 		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
 		return
 	}
+	var req dto.AssetListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid list parameters")
+		return
+	}
 	userID, _ := c.Get("user_id")
-	assets, err := h.service.GetTeamAssets(c.Request.Context(), teamID, userID.(uuid.UUID))
+	assets, err := h.service.GetTeamAssets(c.Request.Context(), teamID, userID.(uuid.UUID), &req)
 	if err != nil {
 		responses.Error(c, http.StatusForbidden, err, "Get team assets failed or access denied")
 		return
@@ -259,8 +870,13 @@ func (h *AssetHandler) GetUserAssets(c *gin.Context) {
 		responses.Error(c, http.StatusBadRequest, err, "Invalid user ID format")
 		return
 	}
+	var req dto.AssetListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid list parameters")
+		return
+	}
 	userID, _ := c.Get("user_id")
-	assets, err := h.service.GetUserAssets(c.Request.Context(), targetUserID, userID.(uuid.UUID))
+	assets, err := h.service.GetUserAssets(c.Request.Context(), targetUserID, userID.(uuid.UUID), &req)
 	if err != nil {
 		responses.Error(c, http.StatusForbidden, err, "Get user assets failed or access denied")
 		return
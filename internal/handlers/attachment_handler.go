@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go_service/internal/repositories"
+	"go_service/internal/services"
+	"go_service/pkg/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AttachmentHandler struct {
+	service     services.IAttachmentService
+	maxFileSize int64
+}
+
+func NewAttachmentHandler(service services.IAttachmentService, maxFileSize int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		service:     service,
+		maxFileSize: maxFileSize,
+	}
+}
+
+// POST /assets/notes/:noteId/attachments (multipart form field "file")
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+
+	// Leave some room for the multipart envelope around the file
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxFileSize+1<<20)
+	file, err := c.FormFile("file")
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "A file is required in the 'file' form field")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	attachment, err := h.service.UploadAttachment(c.Request.Context(), noteID, userID.(uuid.UUID), file)
+	if err != nil {
+		if errors.Is(err, repositories.ErrQuotaExceeded) {
+			responses.Error(c, http.StatusRequestEntityTooLarge, err, "Team storage quota exceeded")
+			return
+		}
+		responses.Error(c, http.StatusForbidden, err, "Upload failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusCreated, gin.H{"success": true, "data": attachment})
+}
+
+// GET /assets/notes/:noteId/attachments
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	attachments, err := h.service.GetAttachments(c.Request.Context(), noteID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Note not found or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": attachments})
+}
+
+// GET /assets/notes/:noteId/attachments/:attachmentId streams the file
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid attachment ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	attachment, content, err := h.service.OpenAttachment(c.Request.Context(), noteID, attachmentID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Attachment not found or access denied")
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.MimeType, content, map[string]string{
+		"Content-Disposition": fmt.Sprintf("attachment; filename=%q", attachment.FileName),
+		"ETag":                `"` + attachment.Checksum + `"`,
+	})
+}
+
+// DELETE /assets/notes/:noteId/attachments/:attachmentId
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid attachment ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.service.DeleteAttachment(c.Request.Context(), noteID, attachmentID, userID.(uuid.UUID)); err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Delete attachment failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Attachment deleted"})
+}
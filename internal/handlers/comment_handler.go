@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go_service/internal/dto"
+	"go_service/internal/services"
+	"go_service/pkg/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CommentHandler struct {
+	service services.ICommentService
+}
+
+func NewCommentHandler(service services.ICommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+// GET /assets/notes/:noteId/comments?status=open|resolved
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	var req dto.CommentListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid comment filter")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	threads, err := h.service.GetComments(c.Request.Context(), noteID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Note not found or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": threads})
+}
+
+// POST /assets/notes/:noteId/comments
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	comment, err := h.service.CreateComment(c.Request.Context(), noteID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Comment failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusCreated, gin.H{"success": true, "data": comment})
+}
+
+// PUT /assets/notes/:noteId/comments/:commentId (author only)
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	noteID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	comment, err := h.service.UpdateComment(c.Request.Context(), noteID, commentID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Update comment failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": comment})
+}
+
+// DELETE /assets/notes/:noteId/comments/:commentId (author only)
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	noteID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.service.DeleteComment(c.Request.Context(), noteID, commentID, userID.(uuid.UUID)); err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Delete comment failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Comment deleted"})
+}
+
+// POST /assets/notes/:noteId/comments/:commentId/resolve
+func (h *CommentHandler) ResolveComment(c *gin.Context) {
+	h.setResolved(c, true)
+}
+
+// POST /assets/notes/:noteId/comments/:commentId/unresolve
+func (h *CommentHandler) UnresolveComment(c *gin.Context) {
+	h.setResolved(c, false)
+}
+
+func (h *CommentHandler) setResolved(c *gin.Context, resolved bool) {
+	noteID, commentID, ok := parseCommentParams(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	comment, err := h.service.SetResolved(c.Request.Context(), noteID, commentID, userID.(uuid.UUID), resolved)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Update thread failed or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": comment})
+}
+
+// parseCommentParams reads the note and comment IDs from the path, answering 400 when either is malformed
+func parseCommentParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	commentID, err := uuid.Parse(c.Param("commentId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid comment ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return noteID, commentID, true
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go_service/internal/dto"
+	"go_service/internal/services"
+	"go_service/pkg/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type GroupHandler struct {
+	service services.IGroupService
+}
+
+func NewGroupHandler(service services.IGroupService) *GroupHandler {
+	return &GroupHandler{
+		service: service,
+	}
+}
+
+// parseTeamGroupIDs reads the :teamId and :groupId route parameters
+func parseTeamGroupIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid group ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return teamID, groupID, true
+}
+
+// POST /teams/:teamId/groups (managers only)
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	var req dto.CreateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	group, err := h.service.CreateGroup(c.Request.Context(), teamID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Failed to create group")
+		return
+	}
+
+	responses.JSON(c, http.StatusCreated, gin.H{"success": true, "message": "Group created successfully", "data": group})
+}
+
+// GET /teams/:teamId/groups (team members)
+func (h *GroupHandler) GetTeamGroups(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	groups, err := h.service.GetTeamGroups(c.Request.Context(), teamID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Failed to retrieve groups")
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": groups})
+}
+
+// PUT /teams/:teamId/groups/:groupId (managers only)
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	teamID, groupID, ok := parseTeamGroupIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	group, err := h.service.UpdateGroup(c.Request.Context(), teamID, groupID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Update group failed or access denied")
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Group updated successfully", "data": group})
+}
+
+// DELETE /teams/:teamId/groups/:groupId (managers only)
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+	teamID, groupID, ok := parseTeamGroupIDs(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.service.DeleteGroup(c.Request.Context(), teamID, groupID, userID.(uuid.UUID)); err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Delete group failed or access denied")
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Group deleted successfully"})
+}
+
+// POST /teams/:teamId/groups/:groupId/members (managers only)
+func (h *GroupHandler) AddGroupMembers(c *gin.Context) {
+	teamID, groupID, ok := parseTeamGroupIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.GroupMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	group, err := h.service.AddGroupMembers(c.Request.Context(), teamID, groupID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Add group members failed or access denied")
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": group})
+}
+
+// DELETE /teams/:teamId/groups/:groupId/members/:userId (managers only)
+func (h *GroupHandler) RemoveGroupMember(c *gin.Context) {
+	teamID, groupID, ok := parseTeamGroupIDs(c)
+	if !ok {
+		return
+	}
+	memberID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid user ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.service.RemoveGroupMember(c.Request.Context(), teamID, groupID, memberID, userID.(uuid.UUID)); err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Remove group member failed or access denied")
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Group member removed"})
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go_service/internal/dto"
+	"go_service/internal/repositories"
+	"go_service/internal/services"
+	"go_service/pkg/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvitationHandler struct {
+	service services.IInvitationService
+}
+
+func NewInvitationHandler(service services.IInvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		service: service,
+	}
+}
+
+// POST /teams/:teamId/invitations (managers invite by user ID or email)
+func (h *InvitationHandler) InviteMember(c *gin.Context) {
+	teamIDStr := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	var req dto.InviteMemberReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	invitation, err := h.service.InviteMember(c.Request.Context(), teamID, req.UserID, req.Email, currentUserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAlreadyInvited) || errors.Is(err, repositories.ErrAlreadyMember) {
+			responses.Error(c, http.StatusConflict, err, "Failed to invite member")
+			return
+		}
+		responses.Error(c, http.StatusBadRequest, err, "Failed to invite member")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Invitation sent successfully",
+		"data":    invitation,
+	}
+	responses.JSON(c, http.StatusCreated, response)
+}
+
+// GET /teams/:teamId/invitations (managers only)
+func (h *InvitationHandler) GetTeamInvitations(c *gin.Context) {
+	teamIDStr := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	invitations, err := h.service.GetTeamInvitations(c.Request.Context(), teamID, currentUserID)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Failed to retrieve team invitations")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Team invitations retrieved successfully",
+		"data":    invitations,
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// GET /invitations
+func (h *InvitationHandler) GetMyInvitations(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	invitations, err := h.service.GetMyInvitations(c.Request.Context(), currentUserID)
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to retrieve invitations")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Invitations retrieved successfully",
+		"data":    invitations,
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// POST /invitations/:token/accept
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	token := c.Param("token")
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	invitation, err := h.service.AcceptInvitation(c.Request.Context(), token, currentUserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAlreadyMember) {
+			responses.Error(c, http.StatusConflict, err, "Failed to accept invitation")
+			return
+		}
+		responses.Error(c, http.StatusBadRequest, err, "Failed to accept invitation")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Invitation accepted successfully",
+		"data":    invitation,
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// POST /invitations/:token/decline
+func (h *InvitationHandler) DeclineInvitation(c *gin.Context) {
+	token := c.Param("token")
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	err := h.service.DeclineInvitation(c.Request.Context(), token, currentUserID)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Failed to decline invitation")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Invitation declined successfully",
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
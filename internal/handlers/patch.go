@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"go_service/internal/dto"
+	"go_service/pkg/responses"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readMergePatch returns the raw body of a JSON merge patch request, answering 415 or 400 on failure
+func readMergePatch(c *gin.Context) ([]byte, bool) {
+	mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if err != nil || (mediaType != dto.MergePatchContentType && mediaType != gin.MIMEJSON) {
+		responses.Error(c, http.StatusUnsupportedMediaType, errors.New("unsupported content type"),
+			"PATCH requests must use "+dto.MergePatchContentType)
+		return nil, false
+	}
+	raw, err := c.GetRawData()
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request body")
+		return nil, false
+	}
+	return raw, true
+}
+
+// respondPatchError answers 422 with per-field messages for validation errors and 400 otherwise
+func respondPatchError(c *gin.Context, err error) {
+	var fieldErrors dto.FieldErrors
+	if errors.As(err, &fieldErrors) {
+		responses.ErrorWithFields(c, http.StatusUnprocessableEntity, err, "Invalid patch",
+			map[string]interface{}{"fields": fieldErrors})
+		return
+	}
+	responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+}
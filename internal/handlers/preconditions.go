@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go_service/internal/services"
+	"go_service/pkg/responses"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag exposes a resource version as a strong ETag
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// parseIfMatch returns the version expected by the If-Match header; 0 means no precondition
+func parseIfMatch(c *gin.Context) (int, error) {
+	value := strings.TrimSpace(c.GetHeader("If-Match"))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	if len(value) < 3 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, errors.New("If-Match must be a single ETag returned by a previous request")
+	}
+	version, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || version < 1 {
+		return 0, errors.New("If-Match must be a single ETag returned by a previous request")
+	}
+	return version, nil
+}
+
+// respondVersionConflict answers 412 with the current version if err is a stale precondition
+func respondVersionConflict(c *gin.Context, err error) bool {
+	var conflict *services.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	setETag(c, conflict.CurrentVersion)
+	responses.ErrorWithFields(c, http.StatusPreconditionFailed, err, "Resource was modified by someone else",
+		map[string]interface{}{"currentVersion": conflict.CurrentVersion})
+	return true
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"go_service/internal/dto"
+	"go_service/internal/services"
+	"go_service/pkg/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// linkPasswordHeader carries the password of a protected public link
+const linkPasswordHeader = "X-Link-Password"
+
+type PublicLinkHandler struct {
+	service services.IPublicLinkService
+}
+
+func NewPublicLinkHandler(service services.IPublicLinkService) *PublicLinkHandler {
+	return &PublicLinkHandler{
+		service: service,
+	}
+}
+
+// POST /assets/folders/:folderId/public-links
+func (h *PublicLinkHandler) CreateFolderLink(c *gin.Context) {
+	h.createLink(c, "folderId", "folder")
+}
+
+// POST /assets/notes/:noteId/public-links
+func (h *PublicLinkHandler) CreateNoteLink(c *gin.Context) {
+	h.createLink(c, "noteId", "note")
+}
+
+func (h *PublicLinkHandler) createLink(c *gin.Context, param, resourceType string) {
+	resourceID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid "+resourceType+" ID format")
+		return
+	}
+
+	// Both fields are optional, so an empty body mints a permanent link without a password
+	var req dto.CreatePublicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	link, err := h.service.CreateLink(c.Request.Context(), resourceID, userID.(uuid.UUID), resourceType, &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Failed to create public link or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusCreated, gin.H{"success": true, "data": link})
+}
+
+// GET /assets/folders/:folderId/public-links
+func (h *PublicLinkHandler) GetFolderLinks(c *gin.Context) {
+	h.getLinks(c, "folderId", "folder")
+}
+
+// GET /assets/notes/:noteId/public-links
+func (h *PublicLinkHandler) GetNoteLinks(c *gin.Context) {
+	h.getLinks(c, "noteId", "note")
+}
+
+func (h *PublicLinkHandler) getLinks(c *gin.Context, param, resourceType string) {
+	resourceID, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid "+resourceType+" ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	links, err := h.service.GetLinks(c.Request.Context(), resourceID, userID.(uuid.UUID), resourceType)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Failed to get public links or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": links})
+}
+
+// DELETE /assets/public-links/:linkId
+func (h *PublicLinkHandler) RevokeLink(c *gin.Context) {
+	linkID, err := uuid.Parse(c.Param("linkId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid link ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.service.RevokeLink(c.Request.Context(), linkID, userID.(uuid.UUID)); err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Failed to revoke public link or access denied")
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Public link revoked successfully"})
+}
+
+// GET /public/links/:token (no authentication)
+func (h *PublicLinkHandler) OpenLink(c *gin.Context) {
+	content, err := h.service.OpenLink(c.Request.Context(), c.Param("token"), c.GetHeader(linkPasswordHeader))
+	h.respondLink(c, content, err)
+}
+
+// GET /public/links/:token/folders/:folderId (no authentication)
+func (h *PublicLinkHandler) OpenLinkFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+	content, err := h.service.OpenLinkFolder(c.Request.Context(), c.Param("token"), c.GetHeader(linkPasswordHeader), folderID)
+	h.respondLink(c, content, err)
+}
+
+// GET /public/links/:token/notes/:noteId (no authentication)
+func (h *PublicLinkHandler) OpenLinkNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+	content, err := h.service.OpenLinkNote(c.Request.Context(), c.Param("token"), c.GetHeader(linkPasswordHeader), noteID)
+	h.respondLink(c, content, err)
+}
+
+func (h *PublicLinkHandler) respondLink(c *gin.Context, content *dto.PublicLinkContent, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPublicLinkPassword):
+			responses.Error(c, http.StatusUnauthorized, err, "This link requires a valid password in the "+linkPasswordHeader+" header")
+		case errors.Is(err, services.ErrPublicLinkUnavailable):
+			responses.Error(c, http.StatusNotFound, err, "Link not found, expired or revoked")
+		default:
+			responses.Error(c, http.StatusInternalServerError, err, "Failed to open public link")
+		}
+		return
+	}
+	// Shared content must not linger in shared caches after the link is revoked
+	c.Header("Cache-Control", "private, no-store")
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": content})
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go_service/internal/services"
+	"go_service/pkg/redisclient"
+	"go_service/pkg/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// streamHeartbeat is how often an idle stream sends a keep-alive and re-checks the subscriber's access
+const streamHeartbeat = 30 * time.Second
+
+type StreamHandler struct {
+	service services.IStreamService
+}
+
+func NewStreamHandler(service services.IStreamService) *StreamHandler {
+	return &StreamHandler{service: service}
+}
+
+// GET /assets/notes/:noteId/stream (Server-Sent Events)
+func (h *StreamHandler) StreamNote(c *gin.Context) {
+	noteID, err := uuid.Parse(c.Param("noteId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid note ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	sub, err := h.service.SubscribeNote(c.Request.Context(), noteID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Note not found or access denied")
+		return
+	}
+	stream(c, sub)
+}
+
+// GET /assets/folders/:folderId/stream (Server-Sent Events)
+func (h *StreamHandler) StreamFolder(c *gin.Context) {
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid folder ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	sub, err := h.service.SubscribeFolder(c.Request.Context(), folderID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Folder not found or access denied")
+		return
+	}
+	stream(c, sub)
+}
+
+// stream writes every change as an SSE event named after its type until the client disconnects,
+// the subscribed resource is deleted or the subscriber loses access
+func stream(c *gin.Context, sub *services.Subscription) {
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // keep reverse proxies from buffering events
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.SSEvent("ready", gin.H{"resourceId": sub.ResourceID})
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case change, ok := <-sub.Changes:
+			if !ok {
+				return false
+			}
+			c.SSEvent(change.Type, change)
+			if change.ResourceID != sub.ResourceID && change.Type != redisclient.ChangeShareChanged {
+				return true
+			}
+			if change.Type == redisclient.ChangeNoteDeleted || change.Type == redisclient.ChangeFolderDeleted {
+				return false
+			}
+			// Moves, owner changes and share changes can all take access away
+			return stillAuthorized(c, sub)
+		case <-heartbeat.C:
+			if !stillAuthorized(c, sub) {
+				return false
+			}
+			fmt.Fprint(w, ": ping\n\n")
+			return true
+		}
+	})
+}
+
+// stillAuthorized ends the stream with a "revoked" event once the subscriber can no longer read the resource
+func stillAuthorized(c *gin.Context, sub *services.Subscription) bool {
+	if err := sub.Authorize(c.Request.Context()); err != nil {
+		c.SSEvent("revoked", gin.H{"resourceId": sub.ResourceID})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go_service/internal/dto"
+	"go_service/internal/services"
+	"go_service/pkg/responses"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TagHandler struct {
+	service services.ITagService
+}
+
+func NewTagHandler(service services.ITagService) *TagHandler {
+	return &TagHandler{
+		service: service,
+	}
+}
+
+// POST /teams/:teamId/tags (any team member)
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	tag, err := h.service.CreateTag(c.Request.Context(), teamID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Failed to create tag")
+		return
+	}
+
+	responses.JSON(c, http.StatusCreated, gin.H{"success": true, "message": "Tag created successfully", "data": tag})
+}
+
+// GET /teams/:teamId/tags (team members)
+func (h *TagHandler) GetTeamTags(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	tags, err := h.service.GetTeamTags(c.Request.Context(), teamID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Failed to retrieve tags")
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": tags})
+}
+
+// PUT /teams/:teamId/tags/:tagId (tag creator or manager)
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+	tagID, err := uuid.Parse(c.Param("tagId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid tag ID format")
+		return
+	}
+
+	var req dto.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	tag, err := h.service.UpdateTag(c.Request.Context(), teamID, tagID, userID.(uuid.UUID), &req)
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Update tag failed or access denied")
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Tag updated successfully", "data": tag})
+}
+
+// DELETE /teams/:teamId/tags/:tagId (tag creator or manager)
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+	tagID, err := uuid.Parse(c.Param("tagId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid tag ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	if err := h.service.DeleteTag(c.Request.Context(), teamID, tagID, userID.(uuid.UUID)); err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Delete tag failed or access denied")
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "message": "Tag deleted successfully"})
+}
+
+// GET /manager/teams/:teamId/tags (managers only)
+func (h *TagHandler) GetTagCounts(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	counts, err := h.service.GetTagCounts(c.Request.Context(), teamID, userID.(uuid.UUID))
+	if err != nil {
+		responses.Error(c, http.StatusForbidden, err, "Failed to retrieve tag counts")
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, gin.H{"success": true, "data": counts})
+}
@@ -37,10 +37,16 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 		return
 	}
 
+	// Members join through invitations so that they can accept or decline
+	if len(req.UserIDs) > 0 {
+		responses.Error(c, http.StatusBadRequest, fmt.Errorf("userIds is not supported"), "Invite members with POST /teams/:teamId/invitations")
+		return
+	}
+
 	userID, _ := c.Get("user_id")
 	creatorID := userID.(uuid.UUID)
 
-	team, failedMembers, err := h.service.CreateTeam(c.Request.Context(), req.TeamName, req.UserIDs, creatorID)
+	team, err := h.service.CreateTeam(c.Request.Context(), req.TeamName, creatorID)
 	if err != nil {
 		responses.Error(c, http.StatusBadRequest, err, "Failed to create team")
 		return
@@ -50,15 +56,14 @@ func (h *TeamHandler) CreateTeam(c *gin.Context) {
 		"success": true,
 		"message": "Team created successfully",
 		"data": gin.H{
-			"team":          team,
-			"failedMembers": failedMembers,
+			"team": team,
 		},
 	}
 	responses.JSON(c, http.StatusCreated, response)
 }
 
-// POST /teams/:teamId/members
-func (h *TeamHandler) AddMemberToTeam(c *gin.Context) {
+// GET /teams/:teamId/members
+func (h *TeamHandler) GetTeamMembers(c *gin.Context) {
 	teamIDStr := c.Param("teamId")
 	teamID, err := uuid.Parse(teamIDStr)
 	if err != nil {
@@ -66,35 +71,66 @@ func (h *TeamHandler) AddMemberToTeam(c *gin.Context) {
 		return
 	}
 
-	var req dto.AddMemberReq
-	if err := c.ShouldBindJSON(&req); err != nil {
-		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+	var req dto.PageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid pagination parameters")
+		return
+	}
+
+	members, err := h.service.GetTeamMembers(c.Request.Context(), teamID, &req)
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to retrieve team members")
+		return
+	}
+	response := gin.H{
+		"success": true,
+		"message": "Team members retrieved successfully",
+		"data":    members,
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// DELETE /teams/:teamId/members/:memberId
+func (h *TeamHandler) RemoveMember(c *gin.Context) {
+	teamIDStr := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	memberIDStr := c.Param("memberId")
+	memberID, err := uuid.Parse(memberIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid member ID format")
+		return
+	}
+
+	var req dto.RemoveMemberReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid offboarding parameters")
 		return
 	}
 
 	userID, _ := c.Get("user_id")
 	currentUserID := userID.(uuid.UUID)
 
-	addedCount, failedCount, failedMembers, err := h.service.AddMembersToTeam(c.Request.Context(), teamID, req.UserIDs, currentUserID)
+	report, err := h.service.RemoveMemberFromTeam(c.Request.Context(), teamID, memberID, currentUserID, &req)
 	if err != nil {
-		responses.Error(c, http.StatusInternalServerError, err, "Failed to add members to team")
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to remove member from team")
 		return
 	}
 
 	response := gin.H{
 		"success": true,
-		"message": "Members added successfully",
-		"data": gin.H{
-			"addedCount":    addedCount,
-			"failedCount":   failedCount,
-			"failedMembers": failedMembers,
-		},
+		"message": "Member removed successfully",
+		"data":    report,
 	}
 	responses.JSON(c, http.StatusOK, response)
 }
 
-// GET /teams/:teamId/members
-func (h *TeamHandler) GetTeamMembers(c *gin.Context) {
+// DELETE /teams/:teamId/managers/:managerId (only MAIN_MANAGER can remove a manager)
+func (h *TeamHandler) RemoveManagerFromTeam(c *gin.Context) {
 	teamIDStr := c.Param("teamId")
 	teamID, err := uuid.Parse(teamIDStr)
 	if err != nil {
@@ -102,21 +138,38 @@ func (h *TeamHandler) GetTeamMembers(c *gin.Context) {
 		return
 	}
 
-	members, err := h.service.GetTeamMembers(c.Request.Context(), teamID)
+	managerIdStr := c.Param("managerId")
+	managerId, err := uuid.Parse(managerIdStr)
 	if err != nil {
-		responses.Error(c, http.StatusInternalServerError, err, "Failed to retrieve team members")
+		responses.Error(c, http.StatusBadRequest, err, "Invalid member ID format")
+		return
+	}
+
+	var req dto.RemoveMemberReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid offboarding parameters")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	report, err := h.service.RemoveManagerFromTeam(c.Request.Context(), teamID, managerId, currentUserID, &req)
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to remove manager from team")
 		return
 	}
+
 	response := gin.H{
 		"success": true,
-		"message": "Team members retrieved successfully",
-		"data":    members,
+		"message": "Manager removed successfully",
+		"data":    report,
 	}
 	responses.JSON(c, http.StatusOK, response)
 }
 
-// DELETE /teams/:teamId/members/:memberId
-func (h *TeamHandler) RemoveMember(c *gin.Context) {
+// POST /teams/:teamId/managers (only MAIN_MANAGER can promote a member)
+func (h *TeamHandler) AddManagerToTeam(c *gin.Context) {
 	teamIDStr := c.Param("teamId")
 	teamID, err := uuid.Parse(teamIDStr)
 	if err != nil {
@@ -124,31 +177,30 @@ func (h *TeamHandler) RemoveMember(c *gin.Context) {
 		return
 	}
 
-	memberIDStr := c.Param("memberId")
-	memberID, err := uuid.Parse(memberIDStr)
-	if err != nil {
-		responses.Error(c, http.StatusBadRequest, err, "Invalid member ID format")
+	var req dto.AddManagerReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
 		return
 	}
 
 	userID, _ := c.Get("user_id")
 	currentUserID := userID.(uuid.UUID)
 
-	err = h.service.RemoveMemberFromTeam(c.Request.Context(), teamID, memberID, currentUserID)
+	err = h.service.AddManagerToTeam(c.Request.Context(), teamID, req.UserID, currentUserID)
 	if err != nil {
-		responses.Error(c, http.StatusInternalServerError, err, "Failed to remove member from team")
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to add manager to team")
 		return
 	}
 
 	response := gin.H{
 		"success": true,
-		"message": "Member removed successfully",
+		"message": "Manager added successfully",
 	}
 	responses.JSON(c, http.StatusOK, response)
 }
 
-// DELETE /teams/:teamId/managers/:managerId (only MAIN_MANAGER can remove a manager)
-func (h *TeamHandler) RemoveManagerFromTeam(c *gin.Context) {
+// POST /teams/:teamId/managers/:managerId/demote (only MAIN_MANAGER can demote a manager)
+func (h *TeamHandler) DemoteManager(c *gin.Context) {
 	teamIDStr := c.Param("teamId")
 	teamID, err := uuid.Parse(teamIDStr)
 	if err != nil {
@@ -166,15 +218,224 @@ func (h *TeamHandler) RemoveManagerFromTeam(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	currentUserID := userID.(uuid.UUID)
 
-	err = h.service.RemoveManagerFromTeam(c.Request.Context(), teamID, managerId, currentUserID)
+	err = h.service.DemoteManager(c.Request.Context(), teamID, managerId, currentUserID)
 	if err != nil {
-		responses.Error(c, http.StatusInternalServerError, err, "Failed to remove manager from team")
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to demote manager")
 		return
 	}
 
 	response := gin.H{
 		"success": true,
-		"message": "Manager removed successfully",
+		"message": "Manager demoted successfully",
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// PUT /teams/:teamId/owner (only MAIN_MANAGER can hand over the team)
+func (h *TeamHandler) TransferOwnership(c *gin.Context) {
+	teamIDStr := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	var req dto.TransferOwnershipReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	err = h.service.TransferOwnership(c.Request.Context(), teamID, req.UserID, currentUserID)
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to transfer team ownership")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Team ownership transferred successfully",
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// PUT /admin/teams/:teamId/owner (only admins, for teams whose owner account was deleted)
+func (h *TeamHandler) ForceTransferOwnership(c *gin.Context) {
+	role, exists := c.Get("role")
+	if !exists || strings.ToUpper(role.(string)) != "ADMIN" {
+		responses.Error(c, http.StatusForbidden, fmt.Errorf("insufficient permissions"), "Only admins can override team ownership")
+		return
+	}
+
+	teamIDStr := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	var req dto.TransferOwnershipReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	adminID := userID.(uuid.UUID)
+
+	err = h.service.ForceTransferOwnership(c.Request.Context(), teamID, req.UserID, adminID)
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to transfer team ownership")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Team ownership transferred successfully",
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// GET /teams
+func (h *TeamHandler) ListTeams(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	teams, err := h.service.GetUserTeams(c.Request.Context(), currentUserID)
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to retrieve teams")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Teams retrieved successfully",
+		"data":    teams,
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// GET /teams/:teamId
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	teamIDStr := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	team, err := h.service.GetTeam(c.Request.Context(), teamID, currentUserID)
+	if err != nil {
+		responses.Error(c, http.StatusNotFound, err, "Team not found or access denied")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Team retrieved successfully",
+		"data":    team,
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// PUT /teams/:teamId (managers can rename a team)
+func (h *TeamHandler) UpdateTeam(c *gin.Context) {
+	teamIDStr := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	var req dto.UpdateTeamReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid request format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	team, err := h.service.RenameTeam(c.Request.Context(), teamID, req.TeamName, currentUserID)
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to update team")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Team updated successfully",
+		"data":    team,
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// POST /teams/:teamId/archive (only MAIN_MANAGER)
+func (h *TeamHandler) ArchiveTeam(c *gin.Context) {
+	h.setTeamArchived(c, true)
+}
+
+// POST /teams/:teamId/unarchive (only MAIN_MANAGER)
+func (h *TeamHandler) UnarchiveTeam(c *gin.Context) {
+	h.setTeamArchived(c, false)
+}
+
+func (h *TeamHandler) setTeamArchived(c *gin.Context, archived bool) {
+	teamIDStr := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	team, err := h.service.SetTeamArchived(c.Request.Context(), teamID, archived, currentUserID)
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to change team archive state")
+		return
+	}
+
+	message := "Team unarchived successfully"
+	if archived {
+		message = "Team archived successfully"
+	}
+	response := gin.H{
+		"success": true,
+		"message": message,
+		"data":    team,
+	}
+	responses.JSON(c, http.StatusOK, response)
+}
+
+// DELETE /teams/:teamId (only MAIN_MANAGER)
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	teamIDStr := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		responses.Error(c, http.StatusBadRequest, err, "Invalid team ID format")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	currentUserID := userID.(uuid.UUID)
+
+	err = h.service.DeleteTeam(c.Request.Context(), teamID, currentUserID)
+	if err != nil {
+		responses.Error(c, http.StatusInternalServerError, err, "Failed to delete team")
+		return
+	}
+
+	response := gin.H{
+		"success": true,
+		"message": "Team deleted successfully",
 	}
 	responses.JSON(c, http.StatusOK, response)
 }
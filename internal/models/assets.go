@@ -8,14 +8,32 @@ import (
 )
 
 type Folder struct {
-	ID   uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
-	Name string    `gorm:"type:varchar(100);not null" json:"name"`
+	ID       uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
+	Name     string     `gorm:"type:varchar(100);not null" json:"name"`
+	ParentID *uuid.UUID `gorm:"type:uuid;index" json:"parentId"` // nil for top-level folders
 
 	OwnerID   uuid.UUID `gorm:"type:uuid;not null" json:"ownerId"`
 	TeamID    uuid.UUID `gorm:"type:uuid;not null" json:"teamId"`
+	Version   int       `gorm:"not null;default:1" json:"version"` // bumped on every update, exposed as ETag
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 	Notes     []Note    `gorm:"foreignkey:FolderID"`
+
+	// Trash bookkeeping: DeletedWith points at the folder whose deletion trashed this one
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deletedAt"`
+	DeletedBy   *uuid.UUID     `gorm:"type:uuid" json:"deletedBy,omitempty"`
+	DeletedWith *uuid.UUID     `gorm:"type:uuid;index" json:"deletedWith,omitempty"`
+
+	// Breadcrumbs from the top-level folder down to this one, filled on reads
+	Path []FolderCrumb `gorm:"-" json:"path,omitempty"`
+
+	// Number of live notes, filled by listings that skip loading notes
+	NoteCount *int64 `gorm:"-" json:"noteCount,omitempty"`
+}
+
+type FolderCrumb struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
 }
 
 func (folder *Folder) BeforeCreate(tx *gorm.DB) (err error) {
@@ -34,8 +52,14 @@ type Note struct {
 	FolderID  uuid.UUID `gorm:"type:uuid;not null" json:"folderId"`
 	OwnerID   uuid.UUID `gorm:"type:uuid;not null" json:"ownerId"`
 	TeamID    uuid.UUID `gorm:"type:uuid;not null" json:"teamId"`
+	Version   int       `gorm:"not null;default:1" json:"version"` // bumped on every update, exposed as ETag
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Trash bookkeeping: DeletedWith points at the folder whose deletion trashed this note
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deletedAt"`
+	DeletedBy   *uuid.UUID     `gorm:"type:uuid" json:"deletedBy,omitempty"`
+	DeletedWith *uuid.UUID     `gorm:"type:uuid;index" json:"deletedWith,omitempty"`
 }
 
 func (note *Note) BeforeCreate(tx *gorm.DB) (err error) {
@@ -47,14 +71,49 @@ func (Note) TableName() string {
 	return "Notes"
 }
 
+// NoteRevision is an immutable snapshot of a note written on every change
+type NoteRevision struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	NoteID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_note_revision" json:"noteId"`
+	Revision  int       `gorm:"not null;uniqueIndex:idx_note_revision" json:"revision"`
+	AuthorID  uuid.UUID `gorm:"type:uuid;not null" json:"authorId"`
+	Title     string    `gorm:"type:varchar(255);not null" json:"title"`
+	Content   string    `gorm:"type:text" json:"content,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (revision *NoteRevision) BeforeCreate(tx *gorm.DB) (err error) {
+	revision.ID = uuid.New()
+	return
+}
+
+func (NoteRevision) TableName() string {
+	return "NoteRevisions"
+}
+
+// Share principals: a single user, every current member of a team, or every member of a group
+const (
+	PrincipalUser  = "user"
+	PrincipalTeam  = "team"
+	PrincipalGroup = "group"
+)
+
+// SharePrincipal identifies who a share is granted to
+type SharePrincipal struct {
+	Type string
+	ID   uuid.UUID
+}
+
 type Share struct {
-	ID           uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
-	ResourceID   uuid.UUID `gorm:"type:uuid;not null" json:"resourceId"`
-	ResourceType string    `gorm:"type:varchar(50);not null" json:"resourceType"` // "folder" or "note"
-	UserID       uuid.UUID `gorm:"type:uuid;not null" json:"userId"`
-	Permission   string    `gorm:"type:varchar(10);not null" json:"permission"` // "read" or "write"
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
+	ResourceID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_share_principal" json:"resourceId"`
+	ResourceType  string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_share_principal" json:"resourceType"` // "folder" or "note"
+	PrincipalType string     `gorm:"type:varchar(10);not null;default:user;uniqueIndex:idx_share_principal" json:"principalType"`
+	PrincipalID   uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_share_principal;index" json:"principalId"` // user, team or group ID
+	Permission    string     `gorm:"type:varchar(10);not null" json:"permission"`                                 // see AccessLevel
+	ExpiresAt     *time.Time `gorm:"index" json:"expiresAt,omitempty"`                                            // nil for permanent shares
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
 }
 
 func (share *Share) BeforeCreate(tx *gorm.DB) (err error) {
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Attachment is the metadata of a file attached to a note; the bytes live in the blob store
+type Attachment struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	NoteID     uuid.UUID `gorm:"type:uuid;not null;index" json:"noteId"`
+	TeamID     uuid.UUID `gorm:"type:uuid;not null;index" json:"teamId"`
+	FileName   string    `gorm:"type:varchar(255);not null" json:"fileName"`
+	Size       int64     `gorm:"not null" json:"size"`
+	MimeType   string    `gorm:"type:varchar(255);not null" json:"mimeType"`
+	Checksum   string    `gorm:"type:varchar(64);not null" json:"checksum"` // hex SHA-256
+	StorageKey string    `gorm:"type:varchar(255);not null" json:"-"`
+	UploadedBy uuid.UUID `gorm:"type:uuid;not null" json:"uploadedBy"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (attachment *Attachment) BeforeCreate(tx *gorm.DB) (err error) {
+	if attachment.ID == uuid.Nil {
+		attachment.ID = uuid.New()
+	}
+	return
+}
+
+func (Attachment) TableName() string {
+	return "Attachments"
+}
+
+// BlobDeletion queues the blob of a deleted attachment. Rows are written in the same transaction that removes
+// the attachment metadata, and the attachment sweeper deletes the bytes afterwards.
+type BlobDeletion struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	StorageKey string    `gorm:"type:varchar(255);not null" json:"storageKey"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (deletion *BlobDeletion) BeforeCreate(tx *gorm.DB) (err error) {
+	if deletion.ID == uuid.Nil {
+		deletion.ID = uuid.New()
+	}
+	return
+}
+
+func (BlobDeletion) TableName() string {
+	return "BlobDeletions"
+}
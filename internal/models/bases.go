@@ -2,7 +2,22 @@ package models
 
 type AccessLevel string
 
+// Share permissions, weakest first; each level includes the ones before it
 const (
-	Read  AccessLevel = "read"
-	Write AccessLevel = "write"
+	Read    AccessLevel = "read"
+	Comment AccessLevel = "comment"
+	Write   AccessLevel = "write"
+	Manage  AccessLevel = "manage" // may also re-share and revoke shares
 )
+
+var accessRanks = map[AccessLevel]int{Read: 1, Comment: 2, Write: 3, Manage: 4}
+
+// Rank orders access levels; unknown and empty levels rank 0
+func (l AccessLevel) Rank() int {
+	return accessRanks[l]
+}
+
+// Includes reports whether holding l also grants other
+func (l AccessLevel) Includes(other AccessLevel) bool {
+	return other.Rank() > 0 && l.Rank() >= other.Rank()
+}
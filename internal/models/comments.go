@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NoteComment is a remark on a note. Replies point at the top-level comment of their thread, and only
+// top-level comments can be resolved. A comment that still has replies is blanked instead of deleted.
+type NoteComment struct {
+	ID         uuid.UUID        `gorm:"type:uuid;primary_key;" json:"id"`
+	NoteID     uuid.UUID        `gorm:"type:uuid;not null;index" json:"noteId"`
+	ParentID   *uuid.UUID       `gorm:"type:uuid;index" json:"parentId,omitempty"`
+	AuthorID   uuid.UUID        `gorm:"type:uuid;not null" json:"authorId"`
+	Body       string           `gorm:"type:text;not null" json:"body"`
+	Mentions   []CommentMention `gorm:"foreignKey:CommentID" json:"mentions"`
+	EditedAt   *time.Time       `json:"editedAt,omitempty"`
+	ResolvedAt *time.Time       `json:"resolvedAt,omitempty"`
+	ResolvedBy *uuid.UUID       `gorm:"type:uuid" json:"resolvedBy,omitempty"`
+	DeletedAt  *time.Time       `json:"deletedAt,omitempty"` // set when the body was removed but replies remain
+	CreatedAt  time.Time        `json:"createdAt"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
+}
+
+func (comment *NoteComment) BeforeCreate(tx *gorm.DB) (err error) {
+	if comment.ID == uuid.Nil {
+		comment.ID = uuid.New()
+	}
+	return
+}
+
+func (NoteComment) TableName() string {
+	return "NoteComments"
+}
+
+// CommentMention is a user @mentioned in a comment
+type CommentMention struct {
+	CommentID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"userId"`
+}
+
+func (CommentMention) TableName() string {
+	return "CommentMentions"
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Group is a named set of team members that folders and notes can be shared with
+type Group struct {
+	ID        uuid.UUID     `gorm:"type:uuid;primary_key;" json:"id"`
+	TeamID    uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex:idx_team_group_name" json:"teamId"`
+	Name      string        `gorm:"type:varchar(100);not null;uniqueIndex:idx_team_group_name" json:"name"`
+	CreatedBy uuid.UUID     `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	Members   []GroupMember `gorm:"foreignKey:GroupID" json:"members,omitempty"`
+}
+
+func (group *Group) BeforeCreate(tx *gorm.DB) (err error) {
+	group.ID = uuid.New()
+	return
+}
+
+func (Group) TableName() string {
+	return "Groups"
+}
+
+type GroupMember struct {
+	GroupID   uuid.UUID `gorm:"type:uuid;primaryKey" json:"groupId"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"userId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (GroupMember) TableName() string {
+	return "GroupMembers"
+}
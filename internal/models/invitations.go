@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	InvitationPending  = "PENDING"
+	InvitationAccepted = "ACCEPTED"
+	InvitationDeclined = "DECLINED"
+)
+
+type Invitation struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	TeamID    uuid.UUID `gorm:"type:uuid;not null;index" json:"teamId"`
+	InviteeID uuid.UUID `gorm:"type:uuid;not null;index" json:"inviteeId"`
+	InvitedBy uuid.UUID `gorm:"type:uuid;not null" json:"invitedBy"`
+	Token     string    `gorm:"type:varchar(64);not null;uniqueIndex" json:"token"`
+	Status    string    `gorm:"type:varchar(20);not null" json:"status"`
+	ExpiresAt time.Time `gorm:"not null" json:"expiresAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	Team Team `gorm:"foreignKey:TeamID;references:ID" json:"team"`
+}
+
+func (invitation *Invitation) BeforeCreate(tx *gorm.DB) (err error) {
+	invitation.ID = uuid.New()
+	return
+}
+
+func (Invitation) TableName() string {
+	return "Invitations"
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Reasons a public link stops working before it expires
+const (
+	LinkRevoked           = "revoked"
+	LinkCreatorLostAccess = "creator_lost_access"
+)
+
+// PublicLink gives anonymous read-only access to a folder or note through an unguessable token
+type PublicLink struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
+	Token          string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"token"`
+	ResourceID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_public_link_resource" json:"resourceId"`
+	ResourceType   string     `gorm:"type:varchar(50);not null;index:idx_public_link_resource" json:"resourceType"` // "folder" or "note"
+	TeamID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"teamId"`
+	CreatedBy      uuid.UUID  `gorm:"type:uuid;not null;index" json:"createdBy"`
+	PasswordHash   string     `gorm:"type:varchar(100)" json:"-"`
+	HasPassword    bool       `gorm:"-" json:"hasPassword"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	ViewCount      int64      `gorm:"not null;default:0" json:"viewCount"`
+	LastViewedAt   *time.Time `json:"lastViewedAt,omitempty"`
+	DisabledAt     *time.Time `json:"disabledAt,omitempty"`
+	DisabledReason string     `gorm:"type:varchar(30)" json:"disabledReason,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+func (link *PublicLink) BeforeCreate(tx *gorm.DB) (err error) {
+	link.ID = uuid.New()
+	return
+}
+
+func (link *PublicLink) AfterFind(tx *gorm.DB) (err error) {
+	link.HasPassword = link.PasswordHash != ""
+	return
+}
+
+func (PublicLink) TableName() string {
+	return "PublicLinks"
+}
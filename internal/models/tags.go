@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag is a team-scoped label; names are stored lower-cased and unique per team
+type Tag struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	TeamID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_team_tag_name" json:"teamId"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_team_tag_name" json:"name"`
+	Color     string    `gorm:"type:varchar(7)" json:"color,omitempty"`
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (tag *Tag) BeforeCreate(tx *gorm.DB) (err error) {
+	tag.ID = uuid.New()
+	return
+}
+
+func (Tag) TableName() string {
+	return "Tags"
+}
+
+type NoteTag struct {
+	NoteID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"noteId"`
+	TagID     uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"tagId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (NoteTag) TableName() string {
+	return "NoteTags"
+}
+
+type FolderTag struct {
+	FolderID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"folderId"`
+	TagID     uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"tagId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (FolderTag) TableName() string {
+	return "FolderTags"
+}
+
+// TagCount reports how many live notes and folders carry a tag
+type TagCount struct {
+	TagID   uuid.UUID `json:"tagId"`
+	Name    string    `json:"name"`
+	Color   string    `json:"color,omitempty"`
+	Notes   int64     `json:"notes"`
+	Folders int64     `json:"folders"`
+}
@@ -10,6 +10,7 @@ import (
 type Team struct {
 	ID        uuid.UUID `gorm:"primary_key;column:id" json:"id"`
 	TeamName  string    `gorm:"size:150;not null;unique;column:team_name" json:"team_name"`
+	Archived  bool      `gorm:"not null;default:false;column:archived" json:"archived"`
 	CreatedAt time.Time `gorm:"column:createdAt" json:"createdAt"`
 	UpdatedAt time.Time `gorm:"column:updatedAt" json:"updatedAt"`
 }
@@ -27,8 +28,8 @@ func (Team) TableName() string {
 }
 
 type Roster struct {
-	TeamID uuid.UUID `gorm:"not null;column:team_id" json:"team_id"`
-	UserID uuid.UUID `gorm:"type:uuid;not null;column:user_id" json:"user_id"`
+	TeamID uuid.UUID `gorm:"not null;column:team_id;uniqueIndex:idx_roster_team_user" json:"team_id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;column:user_id;uniqueIndex:idx_roster_team_user" json:"user_id"`
 	Role   string    `gorm:"default:false;column:role" json:"role"`
 
 	// Foreign key relationships
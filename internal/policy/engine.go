@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go_service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RoleStore looks up team roles; implemented by repositories.TeamRepository
+type RoleStore interface {
+	GetUserRoleInTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (string, error)
+}
+
+// ShareStore looks up the strongest share a user holds; implemented by repositories.AssetRepository
+type ShareStore interface {
+	GetUserShare(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) (*models.Share, error)
+	GetInheritedFolderShare(ctx context.Context, folderID, userID uuid.UUID) (*models.Share, error)
+}
+
+// DeniedError is returned by Engine.Authorize when the matrix rejects an action
+type DeniedError struct {
+	Action Action
+	Type   string
+}
+
+func (e *DeniedError) Error() string {
+	if e.Type == TypeTeam {
+		if e.Action == Create {
+			return "user is not a member of this team"
+		}
+		return "only team managers can do this"
+	}
+	return fmt.Sprintf("you don't have %s access to this %s", e.Action, e.Type)
+}
+
+// Engine answers "can user U perform action A on resource R"
+type Engine struct {
+	roles  RoleStore
+	shares ShareStore
+}
+
+func NewEngine(roles RoleStore, shares ShareStore) *Engine {
+	return &Engine{
+		roles:  roles,
+		shares: shares,
+	}
+}
+
+// Can loads the subject's role and shares and applies Allowed
+func (e *Engine) Can(ctx context.Context, userID uuid.UUID, action Action, resource Resource) (bool, error) {
+	subject, err := e.subject(ctx, userID, action, resource)
+	if err != nil {
+		return false, err
+	}
+	return Allowed(subject, action, resource), nil
+}
+
+// Authorize is Can returning a *DeniedError instead of false
+func (e *Engine) Authorize(ctx context.Context, userID uuid.UUID, action Action, resource Resource) error {
+	allowed, err := e.Can(ctx, userID, action, resource)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return &DeniedError{Action: action, Type: resource.Type}
+	}
+	return nil
+}
+
+// Level returns the effective access level of the user on resource, empty when they have none
+func (e *Engine) Level(ctx context.Context, userID uuid.UUID, resource Resource) (models.AccessLevel, error) {
+	subject, err := e.subject(ctx, userID, Share, resource)
+	if err != nil {
+		return "", err
+	}
+	return Level(subject, resource), nil
+}
+
+// subject only queries shares when they could change the outcome
+func (e *Engine) subject(ctx context.Context, userID uuid.UUID, action Action, resource Resource) (Subject, error) {
+	subject := Subject{UserID: userID}
+
+	role, err := e.roles.GetUserRoleInTeam(ctx, resource.TeamID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return subject, err
+	}
+	subject.Role = role
+
+	if role == "" || IsManager(role) || resource.Type == TypeTeam || userID == resource.OwnerID {
+		return subject, nil
+	}
+	if _, ok := actionLevels[action]; !ok {
+		return subject, nil
+	}
+
+	if resource.Type == TypeNote {
+		share, err := e.shares.GetUserShare(ctx, resource.ID, userID, TypeNote)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return subject, err
+		}
+		if share != nil {
+			subject.Permission = models.AccessLevel(share.Permission)
+		}
+		if subject.Permission.Includes(models.Manage) {
+			return subject, nil
+		}
+	}
+
+	share, err := e.shares.GetInheritedFolderShare(ctx, resource.FolderID, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return subject, err
+	}
+	if share != nil && models.AccessLevel(share.Permission).Rank() > subject.Permission.Rank() {
+		subject.Permission = models.AccessLevel(share.Permission)
+	}
+	return subject, nil
+}
@@ -0,0 +1,144 @@
+// Package policy decides what a user may do with a team's folders and notes.
+//
+// Every asset permission lives in Allowed, so services never compare owners, shares
+// or roles themselves; Engine gathers the facts Allowed needs from the repositories.
+package policy
+
+import (
+	"go_service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Action is something a user attempts on a resource
+type Action string
+
+const (
+	Read     Action = "read"     // view a folder or note, its revisions, tags and attachments
+	Comment  Action = "comment"  // discuss a note without editing it
+	Write    Action = "write"    // edit content and tags, add notes, move things in or out
+	Delete   Action = "delete"   // move to the trash and restore from it
+	Share    Action = "share"    // manage shares and public links
+	Transfer Action = "transfer" // hand ownership to another team member
+	Create   Action = "create"   // create top-level folders in a team
+	Manage   Action = "manage"   // team-wide listings and moving content between teams
+)
+
+// actionLevels is the share level each share-grantable action requires
+var actionLevels = map[Action]models.AccessLevel{
+	Read:    models.Read,
+	Comment: models.Comment,
+	Write:   models.Write,
+	Share:   models.Manage,
+}
+
+// Resource types understood by the engine; folder and note match Share.ResourceType
+const (
+	TypeFolder = "folder"
+	TypeNote   = "note"
+	TypeTeam   = "team"
+)
+
+// Team roles stored on rosters
+const (
+	RoleMember      = "MEMBER"
+	RoleManager     = "MANAGER"
+	RoleMainManager = "MAIN_MANAGER"
+)
+
+// Resource describes the folder, note or team being accessed
+type Resource struct {
+	Type          string
+	ID            uuid.UUID
+	TeamID        uuid.UUID
+	OwnerID       uuid.UUID
+	FolderID      uuid.UUID // the folder itself, or the folder holding the note
+	FolderOwnerID uuid.UUID // owner of FolderID
+}
+
+func FolderResource(folder *models.Folder) Resource {
+	return Resource{
+		Type:          TypeFolder,
+		ID:            folder.ID,
+		TeamID:        folder.TeamID,
+		OwnerID:       folder.OwnerID,
+		FolderID:      folder.ID,
+		FolderOwnerID: folder.OwnerID,
+	}
+}
+
+// NoteResource needs the note's folder because the folder owner has a say over its notes
+func NoteResource(note *models.Note, folder *models.Folder) Resource {
+	return Resource{
+		Type:          TypeNote,
+		ID:            note.ID,
+		TeamID:        folder.TeamID,
+		OwnerID:       note.OwnerID,
+		FolderID:      folder.ID,
+		FolderOwnerID: folder.OwnerID,
+	}
+}
+
+func TeamResource(teamID uuid.UUID) Resource {
+	return Resource{Type: TypeTeam, ID: teamID, TeamID: teamID}
+}
+
+// Subject is what is known about a user with respect to one resource
+type Subject struct {
+	UserID     uuid.UUID
+	Role       string             // role on the resource's team, empty when not a member
+	Permission models.AccessLevel // strongest unexpired share on the resource or a parent folder, empty when none
+}
+
+// IsManager reports whether a team role carries manager rights
+func IsManager(role string) bool {
+	return role == RoleManager || role == RoleMainManager
+}
+
+// Level is the effective access level of a subject: managers and owners hold manage,
+// the owner of a note's folder at least write, everyone else what their shares grant.
+func Level(subject Subject, resource Resource) models.AccessLevel {
+	if subject.Role == "" || resource.Type == TypeTeam {
+		return ""
+	}
+	if IsManager(subject.Role) || subject.UserID == resource.OwnerID {
+		return models.Manage
+	}
+	level := subject.Permission
+	if resource.Type == TypeNote && subject.UserID == resource.FolderOwnerID && !level.Includes(models.Write) {
+		level = models.Write
+	}
+	return level
+}
+
+// Allowed is the permission matrix:
+//
+//   - nobody outside the resource's team may do anything with it
+//   - team managers may do everything
+//   - any member may create top-level folders; only managers may manage the team
+//   - owners may do everything with what they own, including transferring it
+//   - the owner of a folder may delete the notes in it
+//   - otherwise read, comment, write and share need a share of at least that level (share needs manage)
+func Allowed(subject Subject, action Action, resource Resource) bool {
+	if subject.Role == "" {
+		return false
+	}
+	if IsManager(subject.Role) {
+		return true
+	}
+
+	if resource.Type == TypeTeam {
+		return action == Create
+	}
+
+	owner := subject.UserID == resource.OwnerID
+	switch action {
+	case Delete:
+		return owner || (resource.Type == TypeNote && subject.UserID == resource.FolderOwnerID)
+	case Transfer:
+		return owner
+	}
+
+	required, ok := actionLevels[action]
+	return ok && Level(subject, resource).Includes(required)
+}
@@ -0,0 +1,239 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"go_service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	teamID      = uuid.New()
+	owner       = uuid.New()
+	folderOwner = uuid.New()
+	someone     = uuid.New()
+
+	folderRes = Resource{Type: TypeFolder, ID: uuid.New(), TeamID: teamID, OwnerID: owner, FolderOwnerID: owner}
+	noteRes   = Resource{Type: TypeNote, ID: uuid.New(), TeamID: teamID, OwnerID: owner, FolderOwnerID: folderOwner}
+	teamRes   = TeamResource(teamID)
+)
+
+func TestAllowed(t *testing.T) {
+	assetActions := []Action{Read, Comment, Write, Delete, Share, Transfer}
+	everything := assetActions
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		resource Resource
+		allowed  []Action
+	}{
+		{"owner outside the team", Subject{UserID: owner}, folderRes, nil},
+		{"share outside the team", Subject{UserID: someone, Permission: models.Manage}, noteRes, nil},
+		{"folder owner", Subject{UserID: owner, Role: RoleMember}, folderRes, everything},
+		{"note owner", Subject{UserID: owner, Role: RoleMember}, noteRes, everything},
+		{"owner of the note's folder", Subject{UserID: folderOwner, Role: RoleMember}, noteRes, []Action{Read, Comment, Write, Delete}},
+		{"owner of the note's folder with manage share", Subject{UserID: folderOwner, Role: RoleMember, Permission: models.Manage}, noteRes, []Action{Read, Comment, Write, Delete, Share}},
+		{"member without share", Subject{UserID: someone, Role: RoleMember}, noteRes, nil},
+		{"read share on folder", Subject{UserID: someone, Role: RoleMember, Permission: models.Read}, folderRes, []Action{Read}},
+		{"read share on note", Subject{UserID: someone, Role: RoleMember, Permission: models.Read}, noteRes, []Action{Read}},
+		{"comment share on note", Subject{UserID: someone, Role: RoleMember, Permission: models.Comment}, noteRes, []Action{Read, Comment}},
+		{"write share on folder", Subject{UserID: someone, Role: RoleMember, Permission: models.Write}, folderRes, []Action{Read, Comment, Write}},
+		{"write share on note", Subject{UserID: someone, Role: RoleMember, Permission: models.Write}, noteRes, []Action{Read, Comment, Write}},
+		{"manage share on folder", Subject{UserID: someone, Role: RoleMember, Permission: models.Manage}, folderRes, []Action{Read, Comment, Write, Share}},
+		{"unknown share level", Subject{UserID: someone, Role: RoleMember, Permission: "admin"}, noteRes, nil},
+		{"manager on folder", Subject{UserID: someone, Role: RoleManager}, folderRes, everything},
+		{"main manager on note", Subject{UserID: someone, Role: RoleMainManager}, noteRes, everything},
+		{"lowercase manager role", Subject{UserID: someone, Role: "manager"}, noteRes, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, action := range assetActions {
+				want := contains(tt.allowed, action)
+				if got := Allowed(tt.subject, action, tt.resource); got != want {
+					t.Errorf("Allowed(%s) = %v, want %v", action, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject Subject
+		want    models.AccessLevel
+	}{
+		{"not a member", Subject{UserID: owner}, ""},
+		{"owner", Subject{UserID: owner, Role: RoleMember}, models.Manage},
+		{"manager", Subject{UserID: someone, Role: RoleManager}, models.Manage},
+		{"folder owner", Subject{UserID: folderOwner, Role: RoleMember, Permission: models.Read}, models.Write},
+		{"folder owner with manage share", Subject{UserID: folderOwner, Role: RoleMember, Permission: models.Manage}, models.Manage},
+		{"comment share", Subject{UserID: someone, Role: RoleMember, Permission: models.Comment}, models.Comment},
+		{"no share", Subject{UserID: someone, Role: RoleMember}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Level(tt.subject, noteRes); got != tt.want {
+				t.Errorf("Level = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessLevelIncludes(t *testing.T) {
+	levels := []models.AccessLevel{models.Read, models.Comment, models.Write, models.Manage}
+	for i, held := range levels {
+		for j, wanted := range levels {
+			if got := held.Includes(wanted); got != (i >= j) {
+				t.Errorf("%s.Includes(%s) = %v", held, wanted, got)
+			}
+		}
+		if held.Includes("") || models.AccessLevel("").Includes(held) {
+			t.Errorf("empty level must not include or be included by %s", held)
+		}
+	}
+}
+
+func TestAllowedTeam(t *testing.T) {
+	tests := []struct {
+		role   string
+		create bool
+		manage bool
+	}{
+		{"", false, false},
+		{RoleMember, true, false},
+		{RoleManager, true, true},
+		{RoleMainManager, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run("role "+tt.role, func(t *testing.T) {
+			subject := Subject{UserID: someone, Role: tt.role}
+			if got := Allowed(subject, Create, teamRes); got != tt.create {
+				t.Errorf("Allowed(create) = %v, want %v", got, tt.create)
+			}
+			if got := Allowed(subject, Manage, teamRes); got != tt.manage {
+				t.Errorf("Allowed(manage) = %v, want %v", got, tt.manage)
+			}
+		})
+	}
+}
+
+// fakeStore serves roles and shares from maps; missing entries behave like the repositories
+type fakeStore struct {
+	roles        map[uuid.UUID]string
+	noteShares   map[uuid.UUID]models.AccessLevel
+	folderShares map[uuid.UUID]models.AccessLevel
+	shareLookups int
+}
+
+func (f *fakeStore) GetUserRoleInTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (string, error) {
+	role, ok := f.roles[userID]
+	if !ok {
+		return "", gorm.ErrRecordNotFound
+	}
+	return role, nil
+}
+
+func (f *fakeStore) GetUserShare(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) (*models.Share, error) {
+	f.shareLookups++
+	return shareOf(f.noteShares[userID])
+}
+
+func (f *fakeStore) GetInheritedFolderShare(ctx context.Context, folderID, userID uuid.UUID) (*models.Share, error) {
+	f.shareLookups++
+	return shareOf(f.folderShares[userID])
+}
+
+func shareOf(permission models.AccessLevel) (*models.Share, error) {
+	if permission == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &models.Share{Permission: string(permission)}, nil
+}
+
+func TestEngine(t *testing.T) {
+	reader, writer, mixed, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	store := &fakeStore{
+		roles: map[uuid.UUID]string{
+			owner: RoleMember, reader: RoleMember, writer: RoleMember, mixed: RoleMember, someone: RoleManager,
+		},
+		noteShares:   map[uuid.UUID]models.AccessLevel{reader: models.Read, mixed: models.Read, outsider: models.Write},
+		folderShares: map[uuid.UUID]models.AccessLevel{writer: models.Write, mixed: models.Manage},
+	}
+	engine := NewEngine(store, store)
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		action   Action
+		resource Resource
+		want     bool
+	}{
+		{"note share grants read", reader, Read, noteRes, true},
+		{"read note share denies write", reader, Write, noteRes, false},
+		{"note shares do not reach the folder", reader, Read, folderRes, false},
+		{"folder share is inherited by notes", writer, Write, noteRes, true},
+		{"strongest share wins", mixed, Write, noteRes, true},
+		{"inherited manage share allows sharing", mixed, Share, noteRes, true},
+		{"write share cannot delete", writer, Delete, noteRes, false},
+		{"non-member share is ignored", outsider, Read, noteRes, false},
+		{"manager role from roster", someone, Share, noteRes, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := engine.Can(context.Background(), tt.userID, tt.action, tt.resource)
+			if err != nil {
+				t.Fatalf("Can: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Can(%s) = %v, want %v", tt.action, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("owners and managers skip share lookups", func(t *testing.T) {
+		store.shareLookups = 0
+		for _, userID := range []uuid.UUID{owner, someone} {
+			if _, err := engine.Can(context.Background(), userID, Write, noteRes); err != nil {
+				t.Fatalf("Can: %v", err)
+			}
+		}
+		if store.shareLookups != 0 {
+			t.Errorf("share lookups = %d, want 0", store.shareLookups)
+		}
+	})
+
+	t.Run("level is the strongest share", func(t *testing.T) {
+		level, err := engine.Level(context.Background(), mixed, noteRes)
+		if err != nil {
+			t.Fatalf("Level: %v", err)
+		}
+		if level != models.Manage {
+			t.Errorf("Level = %q, want %q", level, models.Manage)
+		}
+	})
+
+	t.Run("denial names action and resource", func(t *testing.T) {
+		err := engine.Authorize(context.Background(), reader, Write, noteRes)
+		if err == nil || err.Error() != "you don't have write access to this note" {
+			t.Errorf("Authorize error = %v", err)
+		}
+	})
+}
+
+func contains(actions []Action, action Action) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
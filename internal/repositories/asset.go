@@ -2,34 +2,67 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"go_service/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// ErrVersionConflict is returned when a guarded update finds a newer version than expected
+var ErrVersionConflict = errors.New("version conflict")
+
 // IAssetRepository defines the interface for asset-related database operations.
 type IAssetRepository interface {
 	// Folder methods
 	CreateFolder(ctx context.Context, folder *models.Folder) error
 	GetFolderByID(ctx context.Context, folderID uuid.UUID) (*models.Folder, error)
-	UpdateFolder(ctx context.Context, folder *models.Folder) error
-	DeleteFolder(ctx context.Context, folderID uuid.UUID) error
+	UpdateFolder(ctx context.Context, folder *models.Folder, expectedVersion int) error
+	DeleteFolder(ctx context.Context, folderID, deletedBy uuid.UUID) error
+	GetFolderSubtree(ctx context.Context, folderID uuid.UUID, maxDepth int) ([]models.Folder, error)
+	GetFolderAncestors(ctx context.Context, folderID uuid.UUID) ([]models.Folder, error)
 
 	// Note methods
-	CreateNote(ctx context.Context, note *models.Note) error
+	CreateNote(ctx context.Context, note *models.Note, tagIDs ...uuid.UUID) error
 	GetNoteByID(ctx context.Context, noteID uuid.UUID) (*models.Note, error)
-	UpdateNote(ctx context.Context, note *models.Note) error
-	DeleteNote(ctx context.Context, noteID uuid.UUID) error
+	UpdateNote(ctx context.Context, note *models.Note, authorID uuid.UUID, expectedVersion int) error
+	MoveNote(ctx context.Context, note *models.Note) error
+	DeleteNote(ctx context.Context, noteID, deletedBy uuid.UUID) error
+
+	// Revision methods
+	GetNoteRevisions(ctx context.Context, noteID uuid.UUID) ([]models.NoteRevision, error)
+	GetNoteRevision(ctx context.Context, noteID uuid.UUID, revision int) (*models.NoteRevision, error)
 
 	// Share methods
-	CreateShare(ctx context.Context, share *models.Share) error
-	GetShare(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) (*models.Share, error)
-	DeleteShare(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) error
+	UpsertShare(ctx context.Context, share *models.Share) (ShareChange, error)
+	UpdateSharePermission(ctx context.Context, resourceID uuid.UUID, resourceType string, principal models.SharePrincipal, permission string) (*models.Share, error)
+	GetShare(ctx context.Context, resourceID uuid.UUID, resourceType string, principal models.SharePrincipal) (*models.Share, error)
+	GetUserShare(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) (*models.Share, error)
+	GetInheritedFolderShare(ctx context.Context, folderID, userID uuid.UUID) (*models.Share, error)
+	DeleteShare(ctx context.Context, resourceID uuid.UUID, resourceType string, principal models.SharePrincipal) error
+	GetResourceShares(ctx context.Context, resourceType string, resourceIDs []uuid.UUID) ([]models.Share, error)
+	GetSharedWithUser(ctx context.Context, filter SharedWithUserFilter) ([]SharedResource, error)
+	DeleteExpiredShares(ctx context.Context, now time.Time) ([]ExpiredShare, error)
+	TransferOwner(ctx context.Context, resourceID uuid.UUID, resourceType string, ownerID uuid.UUID) error
+
+	// Search methods
+	SearchNotes(ctx context.Context, filter NoteSearchFilter) ([]NoteSearchResult, error)
+	GetTaggedNotes(ctx context.Context, userID uuid.UUID, teamID *uuid.UUID, tags TagFilter) ([]models.Note, error)
+	GetTaggedFolders(ctx context.Context, userID uuid.UUID, teamID *uuid.UUID, tags TagFilter) ([]models.Folder, error)
+
+	// Trash methods
+	GetTrashedFolder(ctx context.Context, folderID uuid.UUID) (*models.Folder, error)
+	GetTrashedNote(ctx context.Context, noteID uuid.UUID) (*models.Note, error)
+	RestoreFolder(ctx context.Context, folderID uuid.UUID, detach bool) error
+	RestoreNote(ctx context.Context, noteID uuid.UUID) error
+	GetUserTrash(ctx context.Context, userID uuid.UUID) ([]models.Folder, []models.Note, error)
+	GetTeamTrash(ctx context.Context, teamID uuid.UUID) ([]models.Folder, []models.Note, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
 
 	// Manager methods
-	GetTeamAssets(ctx context.Context, teamID uuid.UUID) ([]models.Folder, error)
-	GetUserAssets(ctx context.Context, userID uuid.UUID) ([]models.Folder, error)
+	GetTeamAssets(ctx context.Context, teamID uuid.UUID, opts FolderListOptions) ([]models.Folder, error)
+	GetUserAssets(ctx context.Context, userID uuid.UUID, teamIDs []uuid.UUID, opts FolderListOptions) ([]models.Folder, error)
 }
 
 // AssetRepository implements IAssetRepository.
@@ -57,27 +90,124 @@ func (r *AssetRepository) GetFolderByID(ctx context.Context, folderID uuid.UUID)
 	return &folder, nil
 }
 
-func (r *AssetRepository) UpdateFolder(ctx context.Context, folder *models.Folder) error {
-	return r.db.WithContext(ctx).Save(folder).Error
+// UpdateFolder writes the folder's name and parent and bumps its version.
+// When expectedVersion is set the update only applies to that version, otherwise ErrVersionConflict is returned.
+func (r *AssetRepository) UpdateFolder(ctx context.Context, folder *models.Folder, expectedVersion int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := updateVersioned(tx.Model(&models.Folder{}), folder.ID, expectedVersion, map[string]interface{}{
+			"name":       folder.Name,
+			"parent_id":  folder.ParentID,
+			"updated_at": folder.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Folder{}).Select("version").Where("id = ?", folder.ID).Scan(&folder.Version).Error
+	})
 }
 
-func (r *AssetRepository) DeleteFolder(ctx context.Context, folderID uuid.UUID) error {
-	// Using transaction to ensure both folder and its notes are deleted.
+// updateVersioned applies values and increments the version column, optionally guarded by expectedVersion
+func updateVersioned(query *gorm.DB, id uuid.UUID, expectedVersion int, values map[string]interface{}) error {
+	values["version"] = gorm.Expr("version + 1")
+	query = query.Where("id = ?", id)
+	if expectedVersion > 0 {
+		query = query.Where("version = ?", expectedVersion)
+	}
+	result := query.Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if expectedVersion > 0 {
+			return ErrVersionConflict
+		}
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteFolder moves the folder, its sub-folders and all their notes to the trash.
+func (r *AssetRepository) DeleteFolder(ctx context.Context, folderID, deletedBy uuid.UUID) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		if err := tx.Where("folder_id = ?", folderID).Delete(&models.Note{}).Error; err != nil {
+		var folderIDs []uuid.UUID
+		if err := tx.Raw(`
+			WITH RECURSIVE tree AS (
+				SELECT id FROM "Folders" WHERE id = ? AND deleted_at IS NULL
+				UNION ALL
+				SELECT f.id FROM "Folders" f JOIN tree t ON f.parent_id = t.id
+				WHERE f.deleted_at IS NULL
+			)
+			SELECT id FROM tree`, folderID).Scan(&folderIDs).Error; err != nil {
 			return err
 		}
-		if err := tx.Where("id = ?", folderID).Delete(&models.Folder{}).Error; err != nil {
+
+		now := time.Now()
+		// Everything trashed along with the folder remembers it, so a restore can bring it back
+		cascaded := map[string]interface{}{"deleted_at": now, "deleted_by": deletedBy, "deleted_with": folderID}
+		if err := tx.Model(&models.Note{}).Where("folder_id IN ?", folderIDs).Updates(cascaded).Error; err != nil {
 			return err
 		}
-		return nil
+		if err := tx.Model(&models.Folder{}).Where("id IN ? AND id <> ?", folderIDs, folderID).Updates(cascaded).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Folder{}).Where("id = ?", folderID).
+			Updates(map[string]interface{}{"deleted_at": now, "deleted_by": deletedBy}).Error
 	})
 }
 
+// GetFolderSubtree returns the folder and its descendants down to maxDepth levels below it.
+func (r *AssetRepository) GetFolderSubtree(ctx context.Context, folderID uuid.UUID, maxDepth int) ([]models.Folder, error) {
+	var folders []models.Folder
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT f.*, 0 AS depth FROM "Folders" f WHERE f.id = ? AND f.deleted_at IS NULL
+			UNION ALL
+			SELECT f.*, t.depth + 1 FROM "Folders" f JOIN tree t ON f.parent_id = t.id
+			WHERE t.depth < ? AND f.deleted_at IS NULL
+		)
+		SELECT * FROM tree ORDER BY depth, name`, folderID, maxDepth).Scan(&folders).Error
+	return folders, err
+}
+
+// GetFolderAncestors returns the chain from the top-level folder down to folderID (inclusive).
+func (r *AssetRepository) GetFolderAncestors(ctx context.Context, folderID uuid.UUID) ([]models.Folder, error) {
+	var folders []models.Folder
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT f.*, 0 AS depth FROM "Folders" f WHERE f.id = ?
+			UNION ALL
+			SELECT f.*, a.depth + 1 FROM "Folders" f JOIN ancestors a ON f.id = a.parent_id
+			WHERE a.depth < 100
+		)
+		SELECT * FROM ancestors ORDER BY depth DESC`, folderID).Scan(&folders).Error
+	return folders, err
+}
+
 // --- Note Methods Implementation ---
 
-func (r *AssetRepository) CreateNote(ctx context.Context, note *models.Note) error {
-	return r.db.WithContext(ctx).Create(note).Error
+// CreateNote inserts the note together with its first revision and the given tags.
+func (r *AssetRepository) CreateNote(ctx context.Context, note *models.Note, tagIDs ...uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(note).Error; err != nil {
+			return err
+		}
+		err := tx.Create(&models.NoteRevision{
+			NoteID:    note.ID,
+			Revision:  1,
+			AuthorID:  note.OwnerID,
+			Title:     note.Title,
+			Content:   note.Content,
+			CreatedAt: note.CreatedAt,
+		}).Error
+		if err != nil || len(tagIDs) == 0 {
+			return err
+		}
+		noteTags := make([]models.NoteTag, len(tagIDs))
+		for i, tagID := range tagIDs {
+			noteTags[i] = models.NoteTag{NoteID: note.ID, TagID: tagID}
+		}
+		return tx.Create(&noteTags).Error
+	})
 }
 
 func (r *AssetRepository) GetNoteByID(ctx context.Context, noteID uuid.UUID) (*models.Note, error) {
@@ -89,47 +219,495 @@ func (r *AssetRepository) GetNoteByID(ctx context.Context, noteID uuid.UUID) (*m
 	return &note, nil
 }
 
-func (r *AssetRepository) UpdateNote(ctx context.Context, note *models.Note) error {
-	return r.db.WithContext(ctx).Save(note).Error
+// UpdateNote saves the note and records the new state as the next revision.
+// When expectedVersion is set the update only applies to that version, otherwise ErrVersionConflict is returned.
+func (r *AssetRepository) UpdateNote(ctx context.Context, note *models.Note, authorID uuid.UUID, expectedVersion int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.NoteRevision{}).
+			Where("note_id = ?", note.ID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		// Notes created before history existed keep their previous state as revision 1
+		if latest == 0 {
+			var previous models.Note
+			if err := tx.First(&previous, "id = ?", note.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.NoteRevision{
+				NoteID:    previous.ID,
+				Revision:  1,
+				AuthorID:  previous.OwnerID,
+				Title:     previous.Title,
+				Content:   previous.Content,
+				CreatedAt: previous.UpdatedAt,
+			}).Error; err != nil {
+				return err
+			}
+			latest = 1
+		}
+
+		err := updateVersioned(tx.Model(&models.Note{}), note.ID, expectedVersion, map[string]interface{}{
+			"title":      note.Title,
+			"content":    note.Content,
+			"updated_at": note.UpdatedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Note{}).Select("version").Where("id = ?", note.ID).Scan(&note.Version).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.NoteRevision{
+			NoteID:    note.ID,
+			Revision:  latest + 1,
+			AuthorID:  authorID,
+			Title:     note.Title,
+			Content:   note.Content,
+			CreatedAt: note.UpdatedAt,
+		}).Error
+	})
 }
 
-func (r *AssetRepository) DeleteNote(ctx context.Context, noteID uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&models.Note{}, "id = ?", noteID).Error
+// MoveNote only rewrites the note's location, so it does not create a revision.
+func (r *AssetRepository) MoveNote(ctx context.Context, note *models.Note) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := updateVersioned(tx.Model(&models.Note{}), note.ID, 0, map[string]interface{}{
+			"folder_id": note.FolderID,
+			"team_id":   note.TeamID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Note{}).Select("version").Where("id = ?", note.ID).Scan(&note.Version).Error; err != nil {
+			return err
+		}
+		// Attachments count against the quota of the team that holds the note
+		err = tx.Model(&models.Attachment{}).Where("note_id = ?", note.ID).Update("team_id", note.TeamID).Error
+		if err != nil {
+			return err
+		}
+		// Tags are team-scoped, so drop the ones that do not belong to the destination team
+		teamTags := tx.Model(&models.Tag{}).Select("id").Where("team_id = ?", note.TeamID)
+		return tx.Where("note_id = ? AND tag_id NOT IN (?)", note.ID, teamTags).Delete(&models.NoteTag{}).Error
+	})
+}
+
+// DeleteNote moves the note to the trash; its revisions are kept until it is purged.
+func (r *AssetRepository) DeleteNote(ctx context.Context, noteID, deletedBy uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Note{}).
+		Where("id = ?", noteID).
+		Updates(map[string]interface{}{"deleted_at": time.Now(), "deleted_by": deletedBy}).Error
+}
+
+// --- Revision Methods Implementation ---
+
+// GetNoteRevisions lists revisions newest first, without their content.
+func (r *AssetRepository) GetNoteRevisions(ctx context.Context, noteID uuid.UUID) ([]models.NoteRevision, error) {
+	var revisions []models.NoteRevision
+	err := r.db.WithContext(ctx).
+		Select("id", "note_id", "revision", "author_id", "title", "created_at").
+		Where("note_id = ?", noteID).
+		Order("revision DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *AssetRepository) GetNoteRevision(ctx context.Context, noteID uuid.UUID, revision int) (*models.NoteRevision, error) {
+	var noteRevision models.NoteRevision
+	err := r.db.WithContext(ctx).
+		Where("note_id = ? AND revision = ?", noteID, revision).
+		First(&noteRevision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &noteRevision, nil
 }
 
 // --- Share Methods Implementation ---
 
-func (r *AssetRepository) CreateShare(ctx context.Context, share *models.Share) error {
-	return r.db.WithContext(ctx).Create(share).Error
+// UpsertShare grants share.Permission to share's principal until share.ExpiresAt, replacing the
+// permission and expiry of an existing share for the same resource and principal. share is filled
+// with the stored row.
+func (r *AssetRepository) UpsertShare(ctx context.Context, share *models.Share) (ShareChange, error) {
+	now := time.Now()
+	var row struct {
+		ID        uuid.UUID
+		CreatedAt time.Time
+		Inserted  bool
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO "Shares" (id, resource_id, resource_type, principal_type, principal_id, permission, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (resource_id, resource_type, principal_type, principal_id) DO UPDATE
+		SET permission = EXCLUDED.permission, expires_at = EXCLUDED.expires_at, updated_at = EXCLUDED.updated_at
+		WHERE "Shares".permission <> EXCLUDED.permission OR "Shares".expires_at IS DISTINCT FROM EXCLUDED.expires_at
+		RETURNING id, created_at, (xmax = 0) AS inserted`,
+		uuid.New(), share.ResourceID, share.ResourceType, share.PrincipalType, share.PrincipalID,
+		share.Permission, share.ExpiresAt, now, now).
+		Scan(&row).Error
+	if err != nil {
+		return "", err
+	}
+
+	// No row is returned when the share already had this permission and expiry
+	if row.ID == uuid.Nil {
+		principal := models.SharePrincipal{Type: share.PrincipalType, ID: share.PrincipalID}
+		existing, err := r.GetShare(ctx, share.ResourceID, share.ResourceType, principal)
+		if err != nil {
+			return "", err
+		}
+		*share = *existing
+		return ShareUnchanged, nil
+	}
+
+	share.ID = row.ID
+	share.CreatedAt = row.CreatedAt
+	share.UpdatedAt = now
+	if row.Inserted {
+		return ShareCreated, nil
+	}
+	return ShareUpdated, nil
+}
+
+// UpdateSharePermission changes the permission of an existing, unexpired share
+func (r *AssetRepository) UpdateSharePermission(ctx context.Context, resourceID uuid.UUID, resourceType string, principal models.SharePrincipal, permission string) (*models.Share, error) {
+	result := r.db.WithContext(ctx).Model(&models.Share{}).
+		Where("resource_id = ? AND resource_type = ? AND principal_type = ? AND principal_id = ?", resourceID, resourceType, principal.Type, principal.ID).
+		Where(unexpiredShare).
+		Updates(map[string]interface{}{"permission": permission, "updated_at": time.Now()})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.GetShare(ctx, resourceID, resourceType, principal)
+}
+
+// GetShare returns the unexpired share granted to exactly this principal
+func (r *AssetRepository) GetShare(ctx context.Context, resourceID uuid.UUID, resourceType string, principal models.SharePrincipal) (*models.Share, error) {
+	var share models.Share
+	err := r.db.WithContext(ctx).
+		Where("resource_id = ? AND resource_type = ? AND principal_type = ? AND principal_id = ?", resourceID, resourceType, principal.Type, principal.ID).
+		Where(unexpiredShare).
+		First(&share).Error
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// GetUserShare returns the strongest unexpired share the user holds on a resource, granted to them
+// directly or through one of their teams or groups.
+func (r *AssetRepository) GetUserShare(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) (*models.Share, error) {
+	var share models.Share
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT s.* FROM "Shares" s
+		WHERE s.resource_id = @resource AND s.resource_type = @type AND `+shareGrantedToUser+` AND `+activeShare+`
+		ORDER BY `+sharePermissionRank+` DESC
+		LIMIT 1`, map[string]interface{}{"resource": resourceID, "type": resourceType, "user": userID}).
+		Scan(&share).Error
+	if err != nil {
+		return nil, err
+	}
+	if share.ID == uuid.Nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &share, nil
 }
 
-func (r *AssetRepository) GetShare(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) (*models.Share, error) {
+// GetInheritedFolderShare returns the strongest unexpired share the user holds on the folder or any of
+// its ancestors, granted to them directly or through one of their teams or groups.
+func (r *AssetRepository) GetInheritedFolderShare(ctx context.Context, folderID, userID uuid.UUID) (*models.Share, error) {
 	var share models.Share
-	err := r.db.WithContext(ctx).Where("resource_id = ? AND user_id = ? AND resource_type = ?", resourceID, userID, resourceType).First(&share).Error
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth FROM "Folders" WHERE id = @folder
+			UNION ALL
+			SELECT f.id, f.parent_id, a.depth + 1 FROM "Folders" f JOIN ancestors a ON f.id = a.parent_id
+			WHERE a.depth < 100
+		)
+		SELECT s.* FROM "Shares" s JOIN ancestors a ON s.resource_id = a.id
+		WHERE s.resource_type = 'folder' AND `+shareGrantedToUser+` AND `+activeShare+`
+		ORDER BY `+sharePermissionRank+` DESC, a.depth
+		LIMIT 1`, map[string]interface{}{"folder": folderID, "user": userID}).Scan(&share).Error
 	if err != nil {
 		return nil, err
 	}
+	if share.ID == uuid.Nil {
+		return nil, gorm.ErrRecordNotFound
+	}
 	return &share, nil
 }
 
-func (r *AssetRepository) DeleteShare(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) error {
-	return r.db.WithContext(ctx).Where("resource_id = ? AND user_id = ? AND resource_type = ?", resourceID, userID, resourceType).Delete(&models.Share{}).Error
+func (r *AssetRepository) DeleteShare(ctx context.Context, resourceID uuid.UUID, resourceType string, principal models.SharePrincipal) error {
+	return r.db.WithContext(ctx).
+		Where("resource_id = ? AND resource_type = ? AND principal_type = ? AND principal_id = ?", resourceID, resourceType, principal.Type, principal.ID).
+		Delete(&models.Share{}).Error
 }
 
-// --- Manager Methods Implementation ---
+// TransferOwner hands a folder or note to ownerID and drops the direct share the new owner no longer needs
+func (r *AssetRepository) TransferOwner(ctx context.Context, resourceID uuid.UUID, resourceType string, ownerID uuid.UUID) error {
+	var model interface{} = &models.Folder{}
+	if resourceType == "note" {
+		model = &models.Note{}
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(model).Where("id = ?", resourceID).Updates(map[string]interface{}{"owner_id": ownerID, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("resource_id = ? AND resource_type = ? AND principal_type = ? AND principal_id = ?",
+			resourceID, resourceType, models.PrincipalUser, ownerID).Delete(&models.Share{}).Error
+	})
+}
 
-func (r *AssetRepository) GetTeamAssets(ctx context.Context, teamID uuid.UUID) ([]models.Folder, error) {
+// GetResourceShares returns the unexpired shares granted on the given folders or notes, oldest first
+func (r *AssetRepository) GetResourceShares(ctx context.Context, resourceType string, resourceIDs []uuid.UUID) ([]models.Share, error) {
+	var shares []models.Share
+	if len(resourceIDs) == 0 {
+		return shares, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("resource_type = ? AND resource_id IN ?", resourceType, resourceIDs).
+		Where(unexpiredShare).
+		Order("created_at").Order("id").
+		Find(&shares).Error
+	return shares, err
+}
+
+// --- Trash Methods Implementation ---
+
+func (r *AssetRepository) GetTrashedFolder(ctx context.Context, folderID uuid.UUID) (*models.Folder, error) {
+	var folder models.Folder
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", folderID).
+		First(&folder).Error
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *AssetRepository) GetTrashedNote(ctx context.Context, noteID uuid.UUID) (*models.Note, error) {
+	var note models.Note
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", noteID).
+		First(&note).Error
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// RestoreFolder brings back a trashed folder and everything that was trashed with it.
+// When detach is set the folder is restored at the top level because its parent is still trashed.
+func (r *AssetRepository) RestoreFolder(ctx context.Context, folderID uuid.UUID, detach bool) error {
+	restored := map[string]interface{}{"deleted_at": nil, "deleted_by": nil, "deleted_with": nil}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Note{}).Where("deleted_with = ?", folderID).Updates(restored).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Folder{}).Where("deleted_with = ?", folderID).Updates(restored).Error; err != nil {
+			return err
+		}
+
+		root := map[string]interface{}{"deleted_at": nil, "deleted_by": nil, "deleted_with": nil}
+		if detach {
+			root["parent_id"] = nil
+		}
+		return tx.Unscoped().Model(&models.Folder{}).Where("id = ?", folderID).Updates(root).Error
+	})
+}
+
+func (r *AssetRepository) RestoreNote(ctx context.Context, noteID uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().
+		Model(&models.Note{}).
+		Where("id = ?", noteID).
+		Updates(map[string]interface{}{"deleted_at": nil, "deleted_by": nil, "deleted_with": nil}).Error
+}
+
+// GetUserTrash lists items the user owns or deleted themselves.
+// Items trashed as part of a folder are only reachable through that folder.
+func (r *AssetRepository) GetUserTrash(ctx context.Context, userID uuid.UUID) ([]models.Folder, []models.Note, error) {
 	var folders []models.Folder
-	// This query can be complex, for now, we get all folders owned by the team.
-	// A more advanced version would include shared assets.
-	err := r.db.WithContext(ctx).Preload("Notes").Where("team_id = ?", teamID).Find(&folders).Error
-	return folders, err
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_with IS NULL AND (owner_id = ? OR deleted_by = ?)", userID, userID).
+		Order("deleted_at DESC").
+		Find(&folders).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var notes []models.Note
+	err = r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_with IS NULL AND (owner_id = ? OR deleted_by = ?)", userID, userID).
+		Order("deleted_at DESC").
+		Find(&notes).Error
+	return folders, notes, err
 }
 
-func (r *AssetRepository) GetUserAssets(ctx context.Context, userID uuid.UUID) ([]models.Folder, error) {
+func (r *AssetRepository) GetTeamTrash(ctx context.Context, teamID uuid.UUID) ([]models.Folder, []models.Note, error) {
 	var folders []models.Folder
-	// This is a simplified query. A complete implementation would require a more complex query
-	// involving joins with the shares table.
-	err := r.db.WithContext(ctx).Preload("Notes").Where("owner_id = ?", userID).Find(&folders).Error
-	return folders, err
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_with IS NULL AND team_id = ?", teamID).
+		Order("deleted_at DESC").
+		Find(&folders).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var notes []models.Note
+	teamFolderIDs := r.db.WithContext(ctx).Unscoped().Model(&models.Folder{}).Select("id").Where("team_id = ?", teamID)
+	err = r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_with IS NULL AND folder_id IN (?)", teamFolderIDs).
+		Order("deleted_at DESC").
+		Find(&notes).Error
+	return folders, notes, err
+}
+
+// PurgeTrash permanently removes items trashed before the given time, with their revisions and shares.
+func (r *AssetRepository) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		noteIDs := tx.Unscoped().Model(&models.Note{}).Select("id").Where("deleted_at < ?", before)
+		if err := tx.Where("note_id IN (?)", noteIDs).Delete(&models.NoteRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("resource_type = ? AND resource_id IN (?)", "note", noteIDs).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("note_id IN (?)", noteIDs).Delete(&models.NoteTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("resource_type = ? AND resource_id IN (?)", "note", noteIDs).Delete(&models.PublicLink{}).Error; err != nil {
+			return err
+		}
+		commentIDs := tx.Model(&models.NoteComment{}).Select("id").Where("note_id IN (?)", noteIDs)
+		if err := tx.Where("comment_id IN (?)", commentIDs).Delete(&models.CommentMention{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("note_id IN (?)", noteIDs).Delete(&models.NoteComment{}).Error; err != nil {
+			return err
+		}
+		if _, err := deleteNoteAttachments(tx, noteIDs); err != nil {
+			return err
+		}
+		notes := tx.Unscoped().Where("deleted_at < ?", before).Delete(&models.Note{})
+		if notes.Error != nil {
+			return notes.Error
+		}
+
+		folderIDs := tx.Unscoped().Model(&models.Folder{}).Select("id").Where("deleted_at < ?", before)
+		if err := tx.Where("resource_type = ? AND resource_id IN (?)", "folder", folderIDs).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("folder_id IN (?)", folderIDs).Delete(&models.FolderTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("resource_type = ? AND resource_id IN (?)", "folder", folderIDs).Delete(&models.PublicLink{}).Error; err != nil {
+			return err
+		}
+		folders := tx.Unscoped().Where("deleted_at < ?", before).Delete(&models.Folder{})
+		if folders.Error != nil {
+			return folders.Error
+		}
+
+		purged = notes.RowsAffected + folders.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+// --- Manager Methods Implementation ---
+
+func (r *AssetRepository) GetTeamAssets(ctx context.Context, teamID uuid.UUID, opts FolderListOptions) ([]models.Folder, error) {
+	return r.listFolders(ctx, r.db.WithContext(ctx).Where("team_id = ?", teamID), opts)
+}
+
+func (r *AssetRepository) GetUserAssets(ctx context.Context, userID uuid.UUID, teamIDs []uuid.UUID, opts FolderListOptions) ([]models.Folder, error) {
+	if len(teamIDs) == 0 {
+		return []models.Folder{}, nil
+	}
+	return r.listFolders(ctx, r.db.WithContext(ctx).Where("owner_id = ? AND team_id IN ?", userID, teamIDs), opts)
+}
+
+// listFolders applies filters, keyset pagination and the note loading mode to a folder query
+func (r *AssetRepository) listFolders(ctx context.Context, query *gorm.DB, opts FolderListOptions) ([]models.Folder, error) {
+	if opts.OwnerID != nil {
+		query = query.Where("owner_id = ?", *opts.OwnerID)
+	}
+	if opts.UpdatedSince != nil {
+		query = query.Where("updated_at >= ?", *opts.UpdatedSince)
+	}
+	if opts.NameContains != "" {
+		query = query.Where("name ILIKE ?", "%"+escapeLike(opts.NameContains)+"%")
+	}
+
+	sortColumn := opts.SortColumn
+	if sortColumn == "" {
+		sortColumn = "name"
+	}
+	direction, comparison := "ASC", ">"
+	if opts.Desc {
+		direction, comparison = "DESC", "<"
+	}
+	if opts.After != nil {
+		query = query.Where("("+sortColumn+", id) "+comparison+" (?, ?)", opts.After.Value, opts.After.ID)
+	}
+	query = query.Order(sortColumn + " " + direction).Order("id " + direction)
+	if opts.Limit > 0 {
+		query = query.Limit(opts.Limit)
+	}
+
+	switch opts.Notes {
+	case NotesFull:
+		query = query.Preload("Notes")
+	case NotesSummary:
+		query = query.Preload("Notes", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "title", "folder_id", "owner_id", "team_id", "created_at", "updated_at")
+		})
+	}
+
+	var folders []models.Folder
+	if err := query.Find(&folders).Error; err != nil {
+		return nil, err
+	}
+
+	if opts.Notes == NotesCount && len(folders) > 0 {
+		folderIDs := make([]uuid.UUID, len(folders))
+		for i, folder := range folders {
+			folderIDs[i] = folder.ID
+		}
+		var counts []struct {
+			FolderID uuid.UUID
+			Count    int64
+		}
+		err := r.db.WithContext(ctx).Model(&models.Note{}).
+			Select("folder_id, count(*) AS count").
+			Where("folder_id IN ?", folderIDs).
+			Group("folder_id").
+			Scan(&counts).Error
+		if err != nil {
+			return nil, err
+		}
+		byFolder := make(map[uuid.UUID]int64, len(counts))
+		for _, c := range counts {
+			byFolder[c.FolderID] = c.Count
+		}
+		for i := range folders {
+			count := byFolder[folders[i].ID]
+			folders[i].NoteCount = &count
+		}
+	}
+
+	return folders, nil
 }
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go_service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrQuotaExceeded is returned when an attachment would push a team over its storage quota
+var ErrQuotaExceeded = errors.New("team storage quota exceeded")
+
+type IAttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *models.Attachment, teamQuota int64) error
+	GetAttachment(ctx context.Context, noteID, attachmentID uuid.UUID) (*models.Attachment, error)
+	GetNoteAttachments(ctx context.Context, noteID uuid.UUID) ([]models.Attachment, error)
+	DeleteAttachment(ctx context.Context, attachmentID uuid.UUID) error
+	GetTeamUsage(ctx context.Context, teamID uuid.UUID) (int64, error)
+	QueueOrphanedAttachments(ctx context.Context) (int64, error)
+	GetBlobDeletions(ctx context.Context, afterID uuid.UUID, limit int) ([]models.BlobDeletion, error)
+	DeleteBlobDeletion(ctx context.Context, deletionID uuid.UUID) error
+}
+
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// CreateAttachment stores the metadata if the team stays within teamQuota bytes.
+// The team row is locked so concurrent uploads cannot both slip under the quota.
+func (r *AttachmentRepository) CreateAttachment(ctx context.Context, attachment *models.Attachment, teamQuota int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var team models.Team
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&team, "id = ?", attachment.TeamID).Error; err != nil {
+			return err
+		}
+
+		var used int64
+		err := tx.Model(&models.Attachment{}).
+			Select("COALESCE(SUM(size), 0)").
+			Where("team_id = ?", attachment.TeamID).
+			Scan(&used).Error
+		if err != nil {
+			return err
+		}
+		if used+attachment.Size > teamQuota {
+			return ErrQuotaExceeded
+		}
+
+		return tx.Create(attachment).Error
+	})
+}
+
+func (r *AttachmentRepository) GetAttachment(ctx context.Context, noteID, attachmentID uuid.UUID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.db.WithContext(ctx).First(&attachment, "id = ? AND note_id = ?", attachmentID, noteID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *AttachmentRepository) GetNoteAttachments(ctx context.Context, noteID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.WithContext(ctx).Where("note_id = ?", noteID).Order("created_at").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *AttachmentRepository) DeleteAttachment(ctx context.Context, attachmentID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", attachmentID).Delete(&models.Attachment{}).Error
+}
+
+// GetTeamUsage sums the attachment bytes stored for a team, including notes in the trash
+func (r *AttachmentRepository) GetTeamUsage(ctx context.Context, teamID uuid.UUID) (int64, error) {
+	var used int64
+	err := r.db.WithContext(ctx).Model(&models.Attachment{}).
+		Select("COALESCE(SUM(size), 0)").
+		Where("team_id = ?", teamID).
+		Scan(&used).Error
+	return used, err
+}
+
+// QueueOrphanedAttachments removes attachments whose note no longer exists and queues their blobs.
+// Purges and team deletions clean up their own attachments; this catches anything left behind.
+func (r *AttachmentRepository) QueueOrphanedAttachments(ctx context.Context) (int64, error) {
+	var queued int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		noteIDs := tx.Model(&models.Attachment{}).Select("note_id").
+			Where(`NOT EXISTS (SELECT 1 FROM "Notes" n WHERE n.id = "Attachments".note_id)`)
+		var err error
+		queued, err = deleteNoteAttachments(tx, noteIDs)
+		return err
+	})
+	return queued, err
+}
+
+// GetBlobDeletions returns queued blob deletions in ID order, starting after afterID
+func (r *AttachmentRepository) GetBlobDeletions(ctx context.Context, afterID uuid.UUID, limit int) ([]models.BlobDeletion, error) {
+	var deletions []models.BlobDeletion
+	err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&deletions).Error
+	return deletions, err
+}
+
+func (r *AttachmentRepository) DeleteBlobDeletion(ctx context.Context, deletionID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("id = ?", deletionID).Delete(&models.BlobDeletion{}).Error
+}
+
+// deleteNoteAttachments removes the attachments of the notes selected by noteIDs inside tx and queues their
+// blobs for deletion, so their bytes stop counting against the team quota as soon as tx commits
+func deleteNoteAttachments(tx *gorm.DB, noteIDs interface{}) (int64, error) {
+	var keys []string
+	if err := tx.Model(&models.Attachment{}).Where("note_id IN (?)", noteIDs).Pluck("storage_key", &keys).Error; err != nil {
+		return 0, err
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	deletions := make([]models.BlobDeletion, len(keys))
+	for i, key := range keys {
+		deletions[i] = models.BlobDeletion{StorageKey: key}
+	}
+	if err := tx.CreateInBatches(&deletions, 500).Error; err != nil {
+		return 0, err
+	}
+	result := tx.Where("note_id IN (?)", noteIDs).Delete(&models.Attachment{})
+	return result.RowsAffected, result.Error
+}
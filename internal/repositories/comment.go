@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"go_service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ICommentRepository interface {
+	CreateComment(ctx context.Context, comment *models.NoteComment) error
+	GetComment(ctx context.Context, noteID, commentID uuid.UUID) (*models.NoteComment, error)
+	GetNoteComments(ctx context.Context, noteID uuid.UUID) ([]models.NoteComment, error)
+	UpdateComment(ctx context.Context, comment *models.NoteComment) error
+	SetResolved(ctx context.Context, commentID uuid.UUID, resolvedBy *uuid.UUID) error
+	DeleteComment(ctx context.Context, comment *models.NoteComment) error
+}
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// CreateComment stores a comment together with its mentions
+func (r *CommentRepository) CreateComment(ctx context.Context, comment *models.NoteComment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+func (r *CommentRepository) GetComment(ctx context.Context, noteID, commentID uuid.UUID) (*models.NoteComment, error) {
+	var comment models.NoteComment
+	err := r.db.WithContext(ctx).Preload("Mentions").First(&comment, "id = ? AND note_id = ?", commentID, noteID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetNoteComments returns every comment and reply on a note, oldest first
+func (r *CommentRepository) GetNoteComments(ctx context.Context, noteID uuid.UUID) ([]models.NoteComment, error) {
+	var comments []models.NoteComment
+	err := r.db.WithContext(ctx).Preload("Mentions").
+		Where("note_id = ?", noteID).
+		Order("created_at").Order("id").
+		Find(&comments).Error
+	return comments, err
+}
+
+// UpdateComment saves the body and edit time of a comment and replaces its mentions
+func (r *CommentRepository) UpdateComment(ctx context.Context, comment *models.NoteComment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.NoteComment{}).Where("id = ?", comment.ID).
+			Updates(map[string]interface{}{"body": comment.Body, "edited_at": comment.EditedAt, "updated_at": time.Now()}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&models.CommentMention{}).Error; err != nil {
+			return err
+		}
+		if len(comment.Mentions) == 0 {
+			return nil
+		}
+		for i := range comment.Mentions {
+			comment.Mentions[i].CommentID = comment.ID
+		}
+		return tx.Create(&comment.Mentions).Error
+	})
+}
+
+// SetResolved marks a thread as resolved by resolvedBy, or reopens it when resolvedBy is nil
+func (r *CommentRepository) SetResolved(ctx context.Context, commentID uuid.UUID, resolvedBy *uuid.UUID) error {
+	var resolvedAt *time.Time
+	if resolvedBy != nil {
+		now := time.Now()
+		resolvedAt = &now
+	}
+	return r.db.WithContext(ctx).Model(&models.NoteComment{}).Where("id = ?", commentID).
+		Updates(map[string]interface{}{"resolved_at": resolvedAt, "resolved_by": resolvedBy, "updated_at": time.Now()}).Error
+}
+
+// DeleteComment removes a comment. A comment with replies keeps its place in the thread with an empty
+// body, and such a placeholder goes away once its last reply is deleted.
+func (r *CommentRepository) DeleteComment(ctx context.Context, comment *models.NoteComment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&models.CommentMention{}).Error; err != nil {
+			return err
+		}
+
+		var replies int64
+		if err := tx.Model(&models.NoteComment{}).Where("parent_id = ?", comment.ID).Count(&replies).Error; err != nil {
+			return err
+		}
+		if replies > 0 {
+			return tx.Model(&models.NoteComment{}).Where("id = ?", comment.ID).
+				Updates(map[string]interface{}{"body": "", "deleted_at": time.Now(), "updated_at": time.Now()}).Error
+		}
+
+		if err := tx.Where("id = ?", comment.ID).Delete(&models.NoteComment{}).Error; err != nil {
+			return err
+		}
+		if comment.ParentID == nil {
+			return nil
+		}
+		return tx.Where("id = ? AND deleted_at IS NOT NULL", *comment.ParentID).
+			Where(`NOT EXISTS (SELECT 1 FROM "NoteComments" c WHERE c.parent_id = ?)`, *comment.ParentID).
+			Delete(&models.NoteComment{}).Error
+	})
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"go_service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IGroupRepository interface {
+	CreateGroup(ctx context.Context, group *models.Group) error
+	GetGroupByID(ctx context.Context, groupID uuid.UUID) (*models.Group, error)
+	GetGroupByName(ctx context.Context, teamID uuid.UUID, name string) (*models.Group, error)
+	GetTeamGroups(ctx context.Context, teamID uuid.UUID) ([]models.Group, error)
+	UpdateGroup(ctx context.Context, group *models.Group) error
+	DeleteGroup(ctx context.Context, groupID uuid.UUID) error
+
+	AddGroupMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error
+	RemoveGroupMember(ctx context.Context, groupID, userID uuid.UUID) error
+}
+
+type GroupRepository struct {
+	db *gorm.DB
+}
+
+func NewGroupRepository(db *gorm.DB) *GroupRepository {
+	return &GroupRepository{db: db}
+}
+
+// CreateGroup inserts the group together with its initial members
+func (r *GroupRepository) CreateGroup(ctx context.Context, group *models.Group) error {
+	return r.db.WithContext(ctx).Create(group).Error
+}
+
+func (r *GroupRepository) GetGroupByID(ctx context.Context, groupID uuid.UUID) (*models.Group, error) {
+	var group models.Group
+	if err := r.db.WithContext(ctx).Preload("Members").First(&group, "id = ?", groupID).Error; err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *GroupRepository) GetGroupByName(ctx context.Context, teamID uuid.UUID, name string) (*models.Group, error) {
+	var group models.Group
+	if err := r.db.WithContext(ctx).First(&group, "team_id = ? AND name = ?", teamID, name).Error; err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *GroupRepository) GetTeamGroups(ctx context.Context, teamID uuid.UUID) ([]models.Group, error) {
+	var groups []models.Group
+	err := r.db.WithContext(ctx).Preload("Members").Where("team_id = ?", teamID).Order("name").Find(&groups).Error
+	return groups, err
+}
+
+func (r *GroupRepository) UpdateGroup(ctx context.Context, group *models.Group) error {
+	return r.db.WithContext(ctx).Model(group).Updates(map[string]interface{}{"name": group.Name, "updated_at": group.UpdatedAt}).Error
+}
+
+// DeleteGroup removes the group, its members and every share granted to it
+func (r *GroupRepository) DeleteGroup(ctx context.Context, groupID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("principal_type = ? AND principal_id = ?", models.PrincipalGroup, groupID).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", groupID).Delete(&models.GroupMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", groupID).Delete(&models.Group{}).Error
+	})
+}
+
+// AddGroupMembers adds users to a group; users already in it are left alone
+func (r *GroupRepository) AddGroupMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	members := make([]models.GroupMember, len(userIDs))
+	for i, userID := range userIDs {
+		members[i] = models.GroupMember{GroupID: groupID, UserID: userID}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&members).Error
+}
+
+func (r *GroupRepository) RemoveGroupMember(ctx context.Context, groupID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&models.GroupMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go_service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAlreadyInvited is returned when the invitee already has a pending invitation to the team
+	ErrAlreadyInvited = errors.New("user already has a pending invitation to this team")
+	// ErrAlreadyMember is returned when the invitee is already on the team's roster
+	ErrAlreadyMember = errors.New("user is already a member of this team")
+)
+
+type IInvitationRepository interface {
+	CreateInvitation(ctx context.Context, invitation *models.Invitation) error
+	GetInvitationByToken(ctx context.Context, token string) (*models.Invitation, error)
+	GetPendingInvitation(ctx context.Context, teamID uuid.UUID, inviteeID uuid.UUID) (*models.Invitation, error)
+	GetPendingInvitationsForUser(ctx context.Context, userID uuid.UUID) ([]models.Invitation, error)
+	GetPendingInvitationsForTeam(ctx context.Context, teamID uuid.UUID) ([]models.Invitation, error)
+	AcceptInvitation(ctx context.Context, invitation *models.Invitation, roster models.Roster) error
+	UpdateInvitationStatus(ctx context.Context, invitationID uuid.UUID, status string) error
+	DeleteExpiredInvitations(ctx context.Context, now time.Time) (int64, error)
+}
+
+type InvitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) *InvitationRepository {
+	return &InvitationRepository{db: db}
+}
+
+// CreateInvitation stores a pending invitation. An expired pending invitation for the same user is replaced;
+// a live one makes this fail with ErrAlreadyInvited.
+func (r *InvitationRepository) CreateInvitation(ctx context.Context, invitation *models.Invitation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("team_id = ? AND invitee_id = ? AND status = ? AND expires_at <= ?",
+			invitation.TeamID, invitation.InviteeID, models.InvitationPending, time.Now()).
+			Delete(&models.Invitation{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(invitation).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrAlreadyInvited
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *InvitationRepository) GetInvitationByToken(ctx context.Context, token string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	if err := r.db.WithContext(ctx).First(&invitation, "token = ?", token).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) GetPendingInvitation(ctx context.Context, teamID uuid.UUID, inviteeID uuid.UUID) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.db.WithContext(ctx).
+		Where("team_id = ? AND invitee_id = ? AND status = ? AND expires_at > ?", teamID, inviteeID, models.InvitationPending, time.Now()).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *InvitationRepository) GetPendingInvitationsForUser(ctx context.Context, userID uuid.UUID) ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	err := r.db.WithContext(ctx).
+		Preload("Team").
+		Where("invitee_id = ? AND status = ? AND expires_at > ?", userID, models.InvitationPending, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+func (r *InvitationRepository) GetPendingInvitationsForTeam(ctx context.Context, teamID uuid.UUID) ([]models.Invitation, error) {
+	var invitations []models.Invitation
+	err := r.db.WithContext(ctx).
+		Preload("Team").
+		Where("team_id = ? AND status = ? AND expires_at > ?", teamID, models.InvitationPending, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	return invitations, err
+}
+
+// marks the invitation accepted and inserts the roster entry in one transaction
+func (r *InvitationRepository) AcceptInvitation(ctx context.Context, invitation *models.Invitation, roster models.Roster) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Guard on status so a token can only be consumed once
+		result := tx.Model(&models.Invitation{}).
+			Where("id = ? AND status = ?", invitation.ID, models.InvitationPending).
+			Update("status", models.InvitationAccepted)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("invitation has already been used")
+		}
+		if err := tx.Create(&roster).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrAlreadyMember
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *InvitationRepository) UpdateInvitationStatus(ctx context.Context, invitationID uuid.UUID, status string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Invitation{}).
+		Where("id = ? AND status = ?", invitationID, models.InvitationPending).
+		Update("status", status)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("invitation has already been used")
+	}
+	return nil
+}
+
+// removes pending invitations whose expiry has passed
+func (r *InvitationRepository) DeleteExpiredInvitations(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", models.InvitationPending, now).
+		Delete(&models.Invitation{})
+	return result.RowsAffected, result.Error
+}
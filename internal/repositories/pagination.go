@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Note loading modes for folder listings
+const (
+	NotesFull    = "full"    // notes with content
+	NotesSummary = "summary" // notes without content
+	NotesCount   = "count"   // only the number of notes per folder
+	NotesNone    = "none"    // folders only
+)
+
+// KeysetCursor is the position of the last row of a page: its sort value and ID
+type KeysetCursor struct {
+	Value interface{}
+	ID    uuid.UUID
+}
+
+// FolderListOptions filters, sorts and paginates folder listings
+type FolderListOptions struct {
+	OwnerID      *uuid.UUID
+	UpdatedSince *time.Time
+	NameContains string
+
+	SortColumn string // name, created_at or updated_at
+	Desc       bool
+	After      *KeysetCursor
+	Limit      int
+
+	Notes string
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
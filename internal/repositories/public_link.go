@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"go_service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type IPublicLinkRepository interface {
+	CreateLink(ctx context.Context, link *models.PublicLink) error
+	GetLinkByID(ctx context.Context, linkID uuid.UUID) (*models.PublicLink, error)
+	GetLinkByToken(ctx context.Context, token string) (*models.PublicLink, error)
+	GetResourceLinks(ctx context.Context, resourceID uuid.UUID, resourceType string) ([]models.PublicLink, error)
+	DisableLink(ctx context.Context, linkID uuid.UUID, reason string) error
+	RecordView(ctx context.Context, linkID uuid.UUID) error
+}
+
+type PublicLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewPublicLinkRepository(db *gorm.DB) *PublicLinkRepository {
+	return &PublicLinkRepository{db: db}
+}
+
+func (r *PublicLinkRepository) CreateLink(ctx context.Context, link *models.PublicLink) error {
+	return r.db.WithContext(ctx).Create(link).Error
+}
+
+func (r *PublicLinkRepository) GetLinkByID(ctx context.Context, linkID uuid.UUID) (*models.PublicLink, error) {
+	var link models.PublicLink
+	if err := r.db.WithContext(ctx).First(&link, "id = ?", linkID).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *PublicLinkRepository) GetLinkByToken(ctx context.Context, token string) (*models.PublicLink, error) {
+	var link models.PublicLink
+	if err := r.db.WithContext(ctx).First(&link, "token = ?", token).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetResourceLinks lists every link of a folder or note, newest first, including disabled ones
+func (r *PublicLinkRepository) GetResourceLinks(ctx context.Context, resourceID uuid.UUID, resourceType string) ([]models.PublicLink, error) {
+	var links []models.PublicLink
+	err := r.db.WithContext(ctx).
+		Where("resource_id = ? AND resource_type = ?", resourceID, resourceType).
+		Order("created_at DESC").
+		Find(&links).Error
+	return links, err
+}
+
+// DisableLink turns a link off for good; already disabled links keep their original reason
+func (r *PublicLinkRepository) DisableLink(ctx context.Context, linkID uuid.UUID, reason string) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&models.PublicLink{}).
+		Where("id = ? AND disabled_at IS NULL", linkID).
+		Updates(map[string]interface{}{"disabled_at": now, "disabled_reason": reason, "updated_at": now}).Error
+}
+
+// RecordView increments the view counter atomically
+func (r *PublicLinkRepository) RecordView(ctx context.Context, linkID uuid.UUID) error {
+	return r.db.WithContext(ctx).Model(&models.PublicLink{}).
+		Where("id = ?", linkID).
+		Updates(map[string]interface{}{"view_count": gorm.Expr("view_count + 1"), "last_viewed_at": time.Now()}).Error
+}
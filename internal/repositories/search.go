@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"context"
+	"go_service/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// accessCTEs resolve the folders shared with @user through unexpired shares (including sub-folders),
+// the teams @user is on and the teams @user manages
+const accessCTEs = `
+	shared_folders AS (
+		SELECT f.id FROM "Folders" f
+		JOIN "Shares" s ON s.resource_id = f.id AND s.resource_type = 'folder'
+			AND ` + shareGrantedToUser + ` AND ` + activeShare + `
+		WHERE f.deleted_at IS NULL
+		UNION
+		SELECT c.id FROM "Folders" c JOIN shared_folders p ON c.parent_id = p.id
+		WHERE c.deleted_at IS NULL
+	),
+	member_teams AS (
+		SELECT team_id, role FROM "Rosters" WHERE user_id = @user
+	),
+	managed_teams AS (
+		SELECT team_id FROM member_teams WHERE role IN ('MANAGER', 'MAIN_MANAGER')
+	)`
+
+// noteAccessCondition mirrors policy.Allowed for reading a note n in folder f
+const noteAccessCondition = `(
+	f.team_id IN (SELECT team_id FROM member_teams) AND (
+		n.owner_id = @user
+		OR f.owner_id = @user
+		OR EXISTS (SELECT 1 FROM "Shares" s WHERE s.resource_type = 'note' AND s.resource_id = n.id
+			AND ` + shareGrantedToUser + ` AND ` + activeShare + `)
+		OR n.folder_id IN (SELECT id FROM shared_folders)
+		OR f.team_id IN (SELECT team_id FROM managed_teams)
+	)
+)`
+
+// folderAccessCondition mirrors policy.Allowed for reading a folder f
+const folderAccessCondition = `(
+	f.team_id IN (SELECT team_id FROM member_teams) AND (
+		f.owner_id = @user
+		OR f.id IN (SELECT id FROM shared_folders)
+		OR f.team_id IN (SELECT team_id FROM managed_teams)
+	)
+)`
+
+// noteSearchVector must stay in sync with the idx_notes_search index created in database.Connect
+const noteSearchVector = `to_tsvector('english', coalesce(n.title, '') || ' ' || coalesce(n.content, ''))`
+
+// TagFilter matches assets by tag name; MatchAll requires every tag (AND), otherwise any (OR)
+type TagFilter struct {
+	Names    []string
+	MatchAll bool
+}
+
+// tagCondition builds the tag predicate for the join table linking column to tags
+func tagCondition(filter TagFilter, joinTable, column, ref string, args map[string]interface{}) string {
+	args["tags"] = filter.Names
+	if filter.MatchAll {
+		args["tagCount"] = len(filter.Names)
+		return `(SELECT count(DISTINCT t.name) FROM "` + joinTable + `" jt JOIN "Tags" t ON t.id = jt.tag_id
+			WHERE jt.` + column + ` = ` + ref + ` AND t.name IN @tags) = @tagCount`
+	}
+	return `EXISTS (SELECT 1 FROM "` + joinTable + `" jt JOIN "Tags" t ON t.id = jt.tag_id
+		WHERE jt.` + column + ` = ` + ref + ` AND t.name IN @tags)`
+}
+
+// NoteSearchFilter narrows a full-text search to notes the user can read
+type NoteSearchFilter struct {
+	Query       string
+	UserID      uuid.UUID
+	TeamID      *uuid.UUID
+	FolderID    *uuid.UUID
+	OwnerID     *uuid.UUID
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Tags        TagFilter
+
+	// Keyset cursor: results strictly after (AfterRank, AfterID) in rank order
+	AfterRank *float64
+	AfterID   *uuid.UUID
+	Limit     int
+}
+
+// NoteSearchResult is a ranked hit with highlighted fragments
+type NoteSearchResult struct {
+	ID             uuid.UUID
+	Title          string
+	FolderID       uuid.UUID
+	OwnerID        uuid.UUID
+	TeamID         uuid.UUID
+	UpdatedAt      time.Time
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+// SearchNotes runs a PostgreSQL full-text search restricted to notes the user owns,
+// has a note share on, can reach through a (parent) folder share, or whose team they manage.
+func (r *AssetRepository) SearchNotes(ctx context.Context, filter NoteSearchFilter) ([]NoteSearchResult, error) {
+	args := map[string]interface{}{
+		"query": filter.Query,
+		"user":  filter.UserID,
+		"limit": filter.Limit,
+	}
+
+	conditions := []string{}
+	if filter.TeamID != nil {
+		conditions = append(conditions, "f.team_id = @team")
+		args["team"] = *filter.TeamID
+	}
+	if filter.FolderID != nil {
+		conditions = append(conditions, "n.folder_id = @folder")
+		args["folder"] = *filter.FolderID
+	}
+	if filter.OwnerID != nil {
+		conditions = append(conditions, "n.owner_id = @owner")
+		args["owner"] = *filter.OwnerID
+	}
+	if filter.UpdatedFrom != nil {
+		conditions = append(conditions, "n.updated_at >= @updatedFrom")
+		args["updatedFrom"] = *filter.UpdatedFrom
+	}
+	if filter.UpdatedTo != nil {
+		conditions = append(conditions, "n.updated_at <= @updatedTo")
+		args["updatedTo"] = *filter.UpdatedTo
+	}
+	if len(filter.Tags.Names) > 0 {
+		conditions = append(conditions, tagCondition(filter.Tags, "NoteTags", "note_id", "n.id", args))
+	}
+	extra := ""
+	if len(conditions) > 0 {
+		extra = " AND " + strings.Join(conditions, " AND ")
+	}
+
+	cursor := ""
+	if filter.AfterRank != nil && filter.AfterID != nil {
+		cursor = "WHERE rank < @afterRank OR (rank = @afterRank AND id > @afterId)"
+		args["afterRank"] = *filter.AfterRank
+		args["afterId"] = *filter.AfterID
+	}
+
+	sql := `
+		WITH RECURSIVE ` + accessCTEs + `,
+		ranked AS (
+			SELECT n.id, n.title, n.folder_id, n.owner_id, f.team_id, n.updated_at,
+				ts_rank(` + noteSearchVector + `, q.query)::float8 AS rank
+			FROM "Notes" n
+			JOIN "Folders" f ON f.id = n.folder_id AND f.deleted_at IS NULL
+			CROSS JOIN websearch_to_tsquery('english', @query) AS q(query)
+			WHERE n.deleted_at IS NULL
+				AND ` + noteSearchVector + ` @@ q.query
+				AND ` + noteAccessCondition + extra + `
+		),
+		page AS (
+			SELECT * FROM ranked ` + cursor + `
+			ORDER BY rank DESC, id
+			LIMIT @limit
+		)
+		SELECT p.*,
+			ts_headline('english', n.title, q.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight,
+			ts_headline('english', coalesce(n.content, ''), q.query, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=10, MaxWords=30') AS snippet
+		FROM page p
+		JOIN "Notes" n ON n.id = p.id
+		CROSS JOIN websearch_to_tsquery('english', @query) AS q(query)
+		ORDER BY p.rank DESC, p.id`
+
+	var results []NoteSearchResult
+	err := r.db.WithContext(ctx).Raw(sql, args).Scan(&results).Error
+	return results, err
+}
+
+// GetTaggedNotes lists live notes matching the tag filter that the user can read
+func (r *AssetRepository) GetTaggedNotes(ctx context.Context, userID uuid.UUID, teamID *uuid.UUID, tags TagFilter) ([]models.Note, error) {
+	args := map[string]interface{}{"user": userID}
+	extra := ""
+	if teamID != nil {
+		extra = " AND f.team_id = @team"
+		args["team"] = *teamID
+	}
+
+	var notes []models.Note
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE `+accessCTEs+`
+		SELECT n.* FROM "Notes" n
+		JOIN "Folders" f ON f.id = n.folder_id AND f.deleted_at IS NULL
+		WHERE n.deleted_at IS NULL
+			AND `+noteAccessCondition+`
+			AND `+tagCondition(tags, "NoteTags", "note_id", "n.id", args)+extra+`
+		ORDER BY n.updated_at DESC`, args).Scan(&notes).Error
+	return notes, err
+}
+
+// GetTaggedFolders lists live folders matching the tag filter that the user can read
+func (r *AssetRepository) GetTaggedFolders(ctx context.Context, userID uuid.UUID, teamID *uuid.UUID, tags TagFilter) ([]models.Folder, error) {
+	args := map[string]interface{}{"user": userID}
+	extra := ""
+	if teamID != nil {
+		extra = " AND f.team_id = @team"
+		args["team"] = *teamID
+	}
+
+	var folders []models.Folder
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE `+accessCTEs+`
+		SELECT f.* FROM "Folders" f
+		WHERE f.deleted_at IS NULL
+			AND `+folderAccessCondition+`
+			AND `+tagCondition(tags, "FolderTags", "folder_id", "f.id", args)+extra+`
+		ORDER BY f.name`, args).Scan(&folders).Error
+	return folders, err
+}
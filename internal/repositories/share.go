@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// activeShare filters out expired shares in queries that alias "Shares" as s
+const activeShare = `(s.expires_at IS NULL OR s.expires_at > now())`
+
+// unexpiredShare is activeShare for queries on "Shares" without an alias
+const unexpiredShare = `expires_at IS NULL OR expires_at > now()`
+
+// shareGrantedToUser matches shares s granted to @user directly, to a team @user is on, or to a group
+// @user belongs to. Group members only count while they are still on the group's team.
+const shareGrantedToUser = `(
+	(s.principal_type = 'user' AND s.principal_id = @user)
+	OR (s.principal_type = 'team' AND s.principal_id IN (SELECT team_id FROM "Rosters" WHERE user_id = @user))
+	OR (s.principal_type = 'group' AND s.principal_id IN (
+		SELECT gm.group_id FROM "GroupMembers" gm
+		JOIN "Groups" g ON g.id = gm.group_id
+		JOIN "Rosters" r ON r.team_id = g.team_id AND r.user_id = gm.user_id
+		WHERE gm.user_id = @user))
+)`
+
+// sharePermissionRank orders shares s by models.AccessLevel, strongest highest
+const sharePermissionRank = `(CASE s.permission WHEN 'manage' THEN 4 WHEN 'write' THEN 3 WHEN 'comment' THEN 2 ELSE 1 END)`
+
+// ShareChange tells what UpsertShare did
+type ShareChange string
+
+const (
+	ShareCreated   ShareChange = "created"
+	ShareUpdated   ShareChange = "updated"
+	ShareUnchanged ShareChange = "unchanged"
+)
+
+// SharedWithUserFilter selects the live folders and notes shared with UserID through unexpired shares, newest share first
+type SharedWithUserFilter struct {
+	UserID       uuid.UUID
+	ResourceType string        // "folder", "note" or empty for both
+	After        *KeysetCursor // Value is the created_at of the last share of the previous page
+	Limit        int
+}
+
+// SharedResource is a folder or note together with the share granting access to it
+type SharedResource struct {
+	ShareID      uuid.UUID
+	ResourceID   uuid.UUID
+	ResourceType string
+	Name         string
+	TeamID       uuid.UUID
+	OwnerID      uuid.UUID
+	Via          string // principal type of the share: "user", "team" or "group"
+	Permission   string
+	ExpiresAt    *time.Time
+	SharedAt     time.Time
+	UpdatedAt    time.Time
+}
+
+// ExpiredShare is a share removed by DeleteExpiredShares, with the owner and team of its resource
+type ExpiredShare struct {
+	ID            uuid.UUID
+	ResourceID    uuid.UUID
+	ResourceType  string
+	PrincipalType string
+	PrincipalID   uuid.UUID
+	Permission    string
+	ExpiresAt     time.Time
+	OwnerID       uuid.UUID
+	TeamID        uuid.UUID
+}
+
+// GetSharedWithUser lists the resources shared with a user, skipping trashed ones and teams the user has left
+func (r *AssetRepository) GetSharedWithUser(ctx context.Context, filter SharedWithUserFilter) ([]SharedResource, error) {
+	args := map[string]interface{}{"user": filter.UserID}
+
+	var sources []string
+	if filter.ResourceType == "" || filter.ResourceType == "folder" {
+		sources = append(sources, `
+			SELECT s.id AS share_id, f.id AS resource_id, 'folder' AS resource_type, f.name AS name,
+				f.team_id, f.owner_id, s.principal_type AS via, s.permission, s.expires_at, s.created_at AS shared_at, f.updated_at
+			FROM "Shares" s JOIN "Folders" f ON f.id = s.resource_id
+			WHERE s.resource_type = 'folder' AND `+shareGrantedToUser+` AND f.deleted_at IS NULL AND `+activeShare+`
+				AND f.team_id IN (SELECT team_id FROM "Rosters" WHERE user_id = @user)`)
+	}
+	if filter.ResourceType == "" || filter.ResourceType == "note" {
+		sources = append(sources, `
+			SELECT s.id AS share_id, n.id AS resource_id, 'note' AS resource_type, n.title AS name,
+				n.team_id, n.owner_id, s.principal_type AS via, s.permission, s.expires_at, s.created_at AS shared_at, n.updated_at
+			FROM "Shares" s JOIN "Notes" n ON n.id = s.resource_id
+			WHERE s.resource_type = 'note' AND `+shareGrantedToUser+` AND n.deleted_at IS NULL AND `+activeShare+`
+				AND n.team_id IN (SELECT team_id FROM "Rosters" WHERE user_id = @user)`)
+	}
+
+	query := `SELECT * FROM (` + strings.Join(sources, " UNION ALL ") + `) shared`
+	if filter.After != nil {
+		query += ` WHERE (shared_at, share_id) < (@afterAt, @afterID)`
+		args["afterAt"] = filter.After.Value
+		args["afterID"] = filter.After.ID
+	}
+	query += ` ORDER BY shared_at DESC, share_id DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT @limit`
+		args["limit"] = filter.Limit
+	}
+
+	var resources []SharedResource
+	err := r.db.WithContext(ctx).Raw(query, args).Scan(&resources).Error
+	return resources, err
+}
+
+// DeleteExpiredShares removes the shares that expired before now and returns them
+func (r *AssetRepository) DeleteExpiredShares(ctx context.Context, now time.Time) ([]ExpiredShare, error) {
+	var expired []ExpiredShare
+	err := r.db.WithContext(ctx).Raw(`
+		WITH expired AS (
+			DELETE FROM "Shares" WHERE expires_at IS NOT NULL AND expires_at <= ?
+			RETURNING id, resource_id, resource_type, principal_type, principal_id, permission, expires_at
+		)
+		SELECT e.*, coalesce(f.owner_id, n.owner_id) AS owner_id, coalesce(f.team_id, n.team_id) AS team_id
+		FROM expired e
+		LEFT JOIN "Folders" f ON e.resource_type = 'folder' AND f.id = e.resource_id
+		LEFT JOIN "Notes" n ON e.resource_type = 'note' AND n.id = e.resource_id`, now).
+		Scan(&expired).Error
+	return expired, err
+}
@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"go_service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ITagRepository interface {
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	GetTagByID(ctx context.Context, tagID uuid.UUID) (*models.Tag, error)
+	GetTagByName(ctx context.Context, teamID uuid.UUID, name string) (*models.Tag, error)
+	GetTeamTags(ctx context.Context, teamID uuid.UUID) ([]models.Tag, error)
+	UpdateTag(ctx context.Context, tag *models.Tag) error
+	DeleteTag(ctx context.Context, tagID uuid.UUID) error
+	GetTagCounts(ctx context.Context, teamID uuid.UUID) ([]models.TagCount, error)
+
+	AttachNoteTag(ctx context.Context, noteID, tagID uuid.UUID) error
+	DetachNoteTag(ctx context.Context, noteID, tagID uuid.UUID) error
+	GetNoteTags(ctx context.Context, noteID uuid.UUID) ([]models.Tag, error)
+	AttachFolderTag(ctx context.Context, folderID, tagID uuid.UUID) error
+	DetachFolderTag(ctx context.Context, folderID, tagID uuid.UUID) error
+	GetFolderTags(ctx context.Context, folderID uuid.UUID) ([]models.Tag, error)
+}
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+func (r *TagRepository) GetTagByID(ctx context.Context, tagID uuid.UUID) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).First(&tag, "id = ?", tagID).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *TagRepository) GetTagByName(ctx context.Context, teamID uuid.UUID, name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).First(&tag, "team_id = ? AND name = ?", teamID, name).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *TagRepository) GetTeamTags(ctx context.Context, teamID uuid.UUID) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.WithContext(ctx).Where("team_id = ?", teamID).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+func (r *TagRepository) UpdateTag(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Save(tag).Error
+}
+
+// DeleteTag removes the tag and detaches it from every note and folder
+func (r *TagRepository) DeleteTag(ctx context.Context, tagID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", tagID).Delete(&models.NoteTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", tagID).Delete(&models.FolderTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", tagID).Delete(&models.Tag{}).Error
+	})
+}
+
+// GetTagCounts counts live (not trashed) notes and folders per tag of a team
+func (r *TagRepository) GetTagCounts(ctx context.Context, teamID uuid.UUID) ([]models.TagCount, error) {
+	var counts []models.TagCount
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT t.id AS tag_id, t.name, t.color,
+			(SELECT count(*) FROM "NoteTags" nt JOIN "Notes" n ON n.id = nt.note_id
+				WHERE nt.tag_id = t.id AND n.deleted_at IS NULL) AS notes,
+			(SELECT count(*) FROM "FolderTags" ft JOIN "Folders" f ON f.id = ft.folder_id
+				WHERE ft.tag_id = t.id AND f.deleted_at IS NULL) AS folders
+		FROM "Tags" t
+		WHERE t.team_id = ?
+		ORDER BY t.name`, teamID).Scan(&counts).Error
+	return counts, err
+}
+
+// AttachNoteTag is idempotent: attaching an already attached tag is a no-op
+func (r *TagRepository) AttachNoteTag(ctx context.Context, noteID, tagID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.NoteTag{NoteID: noteID, TagID: tagID}).Error
+}
+
+func (r *TagRepository) DetachNoteTag(ctx context.Context, noteID, tagID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("note_id = ? AND tag_id = ?", noteID, tagID).Delete(&models.NoteTag{}).Error
+}
+
+func (r *TagRepository) GetNoteTags(ctx context.Context, noteID uuid.UUID) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.WithContext(ctx).
+		Joins(`JOIN "NoteTags" nt ON nt.tag_id = "Tags".id`).
+		Where("nt.note_id = ?", noteID).
+		Order("name").
+		Find(&tags).Error
+	return tags, err
+}
+
+func (r *TagRepository) AttachFolderTag(ctx context.Context, folderID, tagID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.FolderTag{FolderID: folderID, TagID: tagID}).Error
+}
+
+func (r *TagRepository) DetachFolderTag(ctx context.Context, folderID, tagID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("folder_id = ? AND tag_id = ?", folderID, tagID).Delete(&models.FolderTag{}).Error
+}
+
+func (r *TagRepository) GetFolderTags(ctx context.Context, folderID uuid.UUID) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.WithContext(ctx).
+		Joins(`JOIN "FolderTags" ft ON ft.tag_id = "Tags".id`).
+		Where("ft.folder_id = ?", folderID).
+		Order("name").
+		Find(&tags).Error
+	return tags, err
+}
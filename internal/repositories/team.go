@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"go_service/internal/models"
 
 	"github.com/google/uuid"
@@ -12,12 +13,19 @@ type ITeamRepository interface {
 	GetTeamByID(ctx context.Context, teamID uuid.UUID) (*models.Team, error)
 	GetUserRoleInTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (string, error)
 	CreateTeam(ctx context.Context, team *models.Team) error
+	GetTeamByName(ctx context.Context, teamName string) (*models.Team, error)
+	GetTeamsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Team, error)
+	UpdateTeam(ctx context.Context, team *models.Team) error
+	DeleteTeam(ctx context.Context, teamID uuid.UUID) error
 	AddMemberToTeam(ctx context.Context, roster models.Roster) error
 	IsUserInTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) (bool, error)
-	IsManager(ctx context.Context, teamID, userID uuid.UUID) (bool, error)
 	GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]models.Roster, error)
-	RemoveMemberFromTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error
-	GetUserTeamID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
+	RemoveMemberFromTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, plan OffboardingPlan) (*OffboardingResult, error)
+	UpdateMemberRole(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, role string) error
+	GetUserTeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	GetManagedTeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	GetTeamOwnerID(ctx context.Context, teamID uuid.UUID) (uuid.UUID, error)
+	TransferOwnership(ctx context.Context, teamID uuid.UUID, fromUserID uuid.UUID, toUserID uuid.UUID, removePrevious bool) error
 }
 
 type TeamRepository struct {
@@ -54,6 +62,97 @@ func (r *TeamRepository) CreateTeam(ctx context.Context, team *models.Team) erro
 	return r.db.WithContext(ctx).Create(team).Error
 }
 
+func (r *TeamRepository) GetTeamByName(ctx context.Context, teamName string) (*models.Team, error) {
+	var team models.Team
+	if err := r.db.WithContext(ctx).First(&team, "team_name = ?", teamName).Error; err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// returns every team the user has a roster entry in
+func (r *TeamRepository) GetTeamsByUserID(ctx context.Context, userID uuid.UUID) ([]models.Team, error) {
+	var teams []models.Team
+	err := r.db.WithContext(ctx).
+		Joins(`JOIN "Rosters" ON "Rosters".team_id = "Teams".id`).
+		Where(`"Rosters".user_id = ?`, userID).
+		Order("team_name").
+		Find(&teams).Error
+	return teams, err
+}
+
+func (r *TeamRepository) UpdateTeam(ctx context.Context, team *models.Team) error {
+	return r.db.WithContext(ctx).Save(team).Error
+}
+
+// removes a team together with its rosters, invitations, groups, folders, notes, comments, attachments and shares
+func (r *TeamRepository) DeleteTeam(ctx context.Context, teamID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Unscoped so that trashed folders and notes are removed as well
+		folderIDs := tx.Unscoped().Model(&models.Folder{}).Select("id").Where("team_id = ?", teamID)
+		noteIDs := tx.Unscoped().Model(&models.Note{}).Select("id").Where("folder_id IN (?)", folderIDs)
+
+		if err := tx.Where("resource_type = ? AND resource_id IN (?)", "note", noteIDs).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("resource_type = ? AND resource_id IN (?)", "folder", folderIDs).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", teamID).Delete(&models.PublicLink{}).Error; err != nil {
+			return err
+		}
+		groupIDs := tx.Model(&models.Group{}).Select("id").Where("team_id = ?", teamID)
+		if err := tx.Where("principal_type = ? AND principal_id = ?", models.PrincipalTeam, teamID).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("principal_type = ? AND principal_id IN (?)", models.PrincipalGroup, groupIDs).Delete(&models.Share{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id IN (?)", groupIDs).Delete(&models.GroupMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", teamID).Delete(&models.Group{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("note_id IN (?)", noteIDs).Delete(&models.NoteRevision{}).Error; err != nil {
+			return err
+		}
+		commentIDs := tx.Model(&models.NoteComment{}).Select("id").Where("note_id IN (?)", noteIDs)
+		if err := tx.Where("comment_id IN (?)", commentIDs).Delete(&models.CommentMention{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("note_id IN (?)", noteIDs).Delete(&models.NoteComment{}).Error; err != nil {
+			return err
+		}
+		if _, err := deleteNoteAttachments(tx, noteIDs); err != nil {
+			return err
+		}
+		tagIDs := tx.Model(&models.Tag{}).Select("id").Where("team_id = ?", teamID)
+		if err := tx.Where("note_id IN (?) OR tag_id IN (?)", noteIDs, tagIDs).Delete(&models.NoteTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("folder_id IN (?) OR tag_id IN (?)", folderIDs, tagIDs).Delete(&models.FolderTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", teamID).Delete(&models.Tag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("folder_id IN (?)", folderIDs).Delete(&models.Note{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("team_id = ?", teamID).Delete(&models.Folder{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", teamID).Delete(&models.Invitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("team_id = ?", teamID).Delete(&models.Roster{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", teamID).Delete(&models.Team{}).Error
+	})
+}
+
 // inserts a roster entry for a team member
 func (r *TeamRepository) AddMemberToTeam(ctx context.Context, roster models.Roster) error {
 	return r.db.WithContext(ctx).Create(&roster).Error
@@ -79,29 +178,141 @@ func (r *TeamRepository) GetTeamMembers(ctx context.Context, teamID uuid.UUID) (
 	return members, err
 }
 
-func (r *TeamRepository) RemoveMemberFromTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID) error {
-	return r.db.WithContext(ctx).
+// OffboardingPlan says what RemoveMemberFromTeam does with the leaving user's team assets
+type OffboardingPlan struct {
+	ReassignTo   *uuid.UUID // new owner of the user's folders and notes, nil leaves them as they are
+	RevokeShares bool       // delete the shares granted to the user directly on the team's folders and notes
+}
+
+// OffboardingResult lists the assets and shares RemoveMemberFromTeam changed
+type OffboardingResult struct {
+	ReassignedFolders []uuid.UUID
+	ReassignedNotes   []uuid.UUID
+	RevokedShares     []models.Share
+}
+
+// removes a roster entry together with the user's memberships in the team's groups, applying the
+// offboarding plan to the user's folders, notes and shares in the same transaction
+func (r *TeamRepository) RemoveMemberFromTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, plan OffboardingPlan) (*OffboardingResult, error) {
+	result := &OffboardingResult{ReassignedFolders: []uuid.UUID{}, ReassignedNotes: []uuid.UUID{}, RevokedShares: []models.Share{}}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Trashed folders and notes are reassigned as well, so they can still be restored by someone on the team
+		if plan.ReassignTo != nil {
+			if err := tx.Raw(`UPDATE "Folders" SET owner_id = ?, updated_at = now() WHERE team_id = ? AND owner_id = ? RETURNING id`,
+				*plan.ReassignTo, teamID, userID).Scan(&result.ReassignedFolders).Error; err != nil {
+				return err
+			}
+			if err := tx.Raw(`UPDATE "Notes" SET owner_id = ?, updated_at = now() WHERE team_id = ? AND owner_id = ? RETURNING id`,
+				*plan.ReassignTo, teamID, userID).Scan(&result.ReassignedNotes).Error; err != nil {
+				return err
+			}
+			// The new owner no longer needs a direct share on what they now own
+			if err := tx.Where("principal_type = ? AND principal_id = ?", models.PrincipalUser, *plan.ReassignTo).
+				Where("(resource_type = 'folder' AND resource_id IN ?) OR (resource_type = 'note' AND resource_id IN ?)",
+					result.ReassignedFolders, result.ReassignedNotes).
+				Delete(&models.Share{}).Error; err != nil {
+				return err
+			}
+		}
+
+		if plan.RevokeShares {
+			folderIDs := tx.Unscoped().Model(&models.Folder{}).Select("id").Where("team_id = ?", teamID)
+			noteIDs := tx.Unscoped().Model(&models.Note{}).Select("id").Where("team_id = ?", teamID)
+			if err := tx.Raw(`DELETE FROM "Shares" WHERE principal_type = ? AND principal_id = ?
+				AND ((resource_type = 'folder' AND resource_id IN (?)) OR (resource_type = 'note' AND resource_id IN (?)))
+				RETURNING *`, models.PrincipalUser, userID, folderIDs, noteIDs).Scan(&result.RevokedShares).Error; err != nil {
+				return err
+			}
+		}
+
+		groupIDs := tx.Model(&models.Group{}).Select("id").Where("team_id = ?", teamID)
+		if err := tx.Where("user_id = ? AND group_id IN (?)", userID, groupIDs).Delete(&models.GroupMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("team_id = ? AND user_id = ?", teamID, userID).Delete(&models.Roster{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// changes the role of an existing roster entry
+func (r *TeamRepository) UpdateMemberRole(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, role string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Roster{}).
 		Where("team_id = ? AND user_id = ?", teamID, userID).
-		Delete(&models.Roster{}).Error
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (r *TeamRepository) IsManager(ctx context.Context, teamID, userID uuid.UUID) (bool, error) {
-	var count int64
+// returns every team the user belongs to
+func (r *TeamRepository) GetUserTeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var teamIDs []uuid.UUID
 	err := r.db.WithContext(ctx).
 		Model(&models.Roster{}).
-		Where("team_id = ? AND user_id = ? AND role = ?", teamID, userID, "manager").
-		Count(&count).Error
-	return count > 0, err
+		Where("user_id = ?", userID).
+		Pluck("team_id", &teamIDs).Error
+	return teamIDs, err
+}
+
+// returns the teams in which the user is a MANAGER or MAIN_MANAGER
+func (r *TeamRepository) GetManagedTeamIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var teamIDs []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&models.Roster{}).
+		Where("user_id = ? AND role IN ?", userID, []string{"MANAGER", "MAIN_MANAGER"}).
+		Pluck("team_id", &teamIDs).Error
+	return teamIDs, err
 }
 
-func (r *TeamRepository) GetUserTeamID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+// returns the user holding the MAIN_MANAGER role of a team
+func (r *TeamRepository) GetTeamOwnerID(ctx context.Context, teamID uuid.UUID) (uuid.UUID, error) {
 	var roster models.Roster
 	err := r.db.WithContext(ctx).
-		Select("team_id").
-		Where("user_id = ?", userID).
+		Select("user_id").
+		Where("team_id = ? AND role = ?", teamID, "MAIN_MANAGER").
 		First(&roster).Error
 	if err != nil {
 		return uuid.Nil, err
 	}
-	return roster.TeamID, nil
+	return roster.UserID, nil
+}
+
+// hands MAIN_MANAGER to another member; the previous owner is demoted to MANAGER or removed from the team
+func (r *TeamRepository) TransferOwnership(ctx context.Context, teamID uuid.UUID, fromUserID uuid.UUID, toUserID uuid.UUID, removePrevious bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var previous *gorm.DB
+		if removePrevious {
+			previous = tx.Where("team_id = ? AND user_id = ? AND role = ?", teamID, fromUserID, "MAIN_MANAGER").
+				Delete(&models.Roster{})
+		} else {
+			previous = tx.Model(&models.Roster{}).
+				Where("team_id = ? AND user_id = ? AND role = ?", teamID, fromUserID, "MAIN_MANAGER").
+				Update("role", "MANAGER")
+		}
+		if previous.Error != nil {
+			return previous.Error
+		}
+		if previous.RowsAffected == 0 {
+			return errors.New("current owner no longer holds the main manager role")
+		}
+
+		next := tx.Model(&models.Roster{}).
+			Where("team_id = ? AND user_id = ?", teamID, toUserID).
+			Update("role", "MAIN_MANAGER")
+		if next.Error != nil {
+			return next.Error
+		}
+		if next.RowsAffected == 0 {
+			return errors.New("new owner is not a member of this team")
+		}
+		return nil
+	})
 }
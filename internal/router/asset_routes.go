@@ -1,20 +1,61 @@
 package router
 
 import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
 	"go_service/internal/handlers"
 	"go_service/internal/repositories"
 	"go_service/internal/services"
+	"go_service/pkg/blobstore"
+	"go_service/pkg/kafka"
+	"go_service/pkg/redisclient"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func AssetRoutes(router *gin.RouterGroup, db *gorm.DB) {
+func AssetRoutes(router *gin.RouterGroup, publicRouter *gin.RouterGroup, db *gorm.DB, producer *kafka.Producer, changes *redisclient.ChangeFeed) {
 	assetRepo := repositories.NewAssetRepository(db)
 	teamRepo := repositories.NewTeamRepository(db)
-	assetService := services.NewAssetService(assetRepo, teamRepo)
+	tagRepo := repositories.NewTagRepository(db)
+	groupRepo := repositories.NewGroupRepository(db)
+	assetService := services.NewAssetService(assetRepo, teamRepo, tagRepo, groupRepo, producer, changes)
 	assetHandler := handlers.NewAssetHandler(assetService)
 
+	// Trashed items are purged after TRASH_RETENTION_DAYS (30 by default)
+	retentionDays, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || retentionDays <= 0 {
+		retentionDays = 30
+	}
+	go assetService.RunTrashPurge(context.Background(), time.Hour, time.Duration(retentionDays)*24*time.Hour)
+	go assetService.RunShareExpiry(context.Background(), time.Minute)
+
+	// Attachment bytes go to the blob store selected by BLOB_STORE
+	blobStore, err := blobstore.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure blob store: %v", err)
+	}
+	limits := services.AttachmentLimits{
+		MaxFileSize: envMegabytes("ATTACHMENT_MAX_SIZE_MB", 25),
+		TeamQuota:   envMegabytes("ATTACHMENT_TEAM_QUOTA_MB", 1024),
+	}
+	attachmentService := services.NewAttachmentService(repositories.NewAttachmentRepository(db), assetService, blobStore, limits)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService, limits.MaxFileSize)
+	go attachmentService.RunOrphanSweep(context.Background(), time.Hour)
+
+	publicLinkService := services.NewPublicLinkService(repositories.NewPublicLinkRepository(db), assetService)
+	publicLinkHandler := handlers.NewPublicLinkHandler(publicLinkService)
+
+	commentService := services.NewCommentService(repositories.NewCommentRepository(db), assetService, producer)
+	commentHandler := handlers.NewCommentHandler(commentService)
+
+	// Live updates fan out across instances through Redis pub/sub
+	streamHandler := handlers.NewStreamHandler(services.NewStreamService(assetService, changes))
+
 	assetRouter := router.Group("/assets")
 	{
 		// Folder routes
@@ -23,7 +64,12 @@ func AssetRoutes(router *gin.RouterGroup, db *gorm.DB) {
 			folders.POST("", assetHandler.CreateFolder)
 			folders.GET("/:folderId", assetHandler.GetFolder)
 			folders.PUT("/:folderId", assetHandler.UpdateFolder)
+			folders.PATCH("/:folderId", assetHandler.PatchFolder)
 			folders.DELETE("/:folderId", assetHandler.DeleteFolder)
+			folders.GET("/:folderId/tree", assetHandler.GetFolderTree)
+			folders.PUT("/:folderId/move", assetHandler.MoveFolder)
+			folders.PUT("/:folderId/owner", assetHandler.TransferFolder)
+			folders.GET("/:folderId/stream", streamHandler.StreamFolder)
 		}
 
 		// Note routes
@@ -31,7 +77,12 @@ func AssetRoutes(router *gin.RouterGroup, db *gorm.DB) {
 		{
 			notes.GET("/:noteId", assetHandler.GetNote)
 			notes.PUT("/:noteId", assetHandler.UpdateNote)
+			notes.PATCH("/:noteId", assetHandler.PatchNote)
 			notes.DELETE("/:noteId", assetHandler.DeleteNote)
+			notes.POST("/:noteId/move", assetHandler.MoveNote)
+			notes.POST("/:noteId/copy", assetHandler.CopyNote)
+			notes.PUT("/:noteId/owner", assetHandler.TransferNote)
+			notes.GET("/:noteId/stream", streamHandler.StreamNote)
 		}
 
 		// Nested note routes
@@ -40,23 +91,109 @@ func AssetRoutes(router *gin.RouterGroup, db *gorm.DB) {
 			folderNotes.POST("", assetHandler.CreateNote)
 		}
 
+		// Attachment routes
+		noteAttachments := notes.Group("/:noteId/attachments")
+		{
+			noteAttachments.POST("", attachmentHandler.UploadAttachment)
+			noteAttachments.GET("", attachmentHandler.GetAttachments)
+			noteAttachments.GET("/:attachmentId", attachmentHandler.DownloadAttachment)
+			noteAttachments.DELETE("/:attachmentId", attachmentHandler.DeleteAttachment)
+		}
+
+		// Comment routes
+		noteComments := notes.Group("/:noteId/comments")
+		{
+			noteComments.GET("", commentHandler.GetComments)
+			noteComments.POST("", commentHandler.CreateComment)
+			noteComments.PUT("/:commentId", commentHandler.UpdateComment)
+			noteComments.DELETE("/:commentId", commentHandler.DeleteComment)
+			noteComments.POST("/:commentId/resolve", commentHandler.ResolveComment)
+			noteComments.POST("/:commentId/unresolve", commentHandler.UnresolveComment)
+		}
+
+		// Revision routes
+		noteRevisions := notes.Group("/:noteId/revisions")
+		{
+			noteRevisions.GET("", assetHandler.GetNoteRevisions)
+			noteRevisions.GET("/diff", assetHandler.DiffNoteRevisions)
+			noteRevisions.GET("/:revision", assetHandler.GetNoteRevision)
+			noteRevisions.POST("/:revision/restore", assetHandler.RestoreNoteRevision)
+		}
+
+		assetRouter.GET("/search", assetHandler.SearchNotes)
+		assetRouter.GET("/tagged", assetHandler.GetTaggedAssets)
+
+		// Tag routes
+		folderTags := folders.Group("/:folderId/tags")
+		{
+			folderTags.GET("", assetHandler.GetFolderTags)
+			folderTags.POST("", assetHandler.AttachFolderTag)
+			folderTags.DELETE("/:tagId", assetHandler.DetachFolderTag)
+		}
+
+		noteTags := notes.Group("/:noteId/tags")
+		{
+			noteTags.GET("", assetHandler.GetNoteTags)
+			noteTags.POST("", assetHandler.AttachNoteTag)
+			noteTags.DELETE("/:tagId", assetHandler.DetachNoteTag)
+		}
+
+		// Trash routes
+		trash := assetRouter.Group("/trash")
+		{
+			trash.GET("", assetHandler.GetMyTrash)
+			trash.POST("/folders/:folderId/restore", assetHandler.RestoreFolder)
+			trash.POST("/notes/:noteId/restore", assetHandler.RestoreNote)
+		}
+
 		// Sharing routes
 		folderShares := folders.Group("/:folderId/shares")
 		{
+			folderShares.GET("", assetHandler.GetFolderShares)
 			folderShares.POST("", assetHandler.ShareFolder)
-			folderShares.DELETE("/:userId", assetHandler.RevokeFolderShare)
+			folderShares.PUT("/:principalId", assetHandler.UpdateFolderShare)
+			folderShares.DELETE("/:principalId", assetHandler.RevokeFolderShare)
 		}
 
 		noteShares := notes.Group("/:noteId/shares")
 		{
+			noteShares.GET("", assetHandler.GetNoteShares)
 			noteShares.POST("", assetHandler.ShareNote)
-			noteShares.DELETE("/:userId", assetHandler.RevokeNoteShare)
+			noteShares.PUT("/:principalId", assetHandler.UpdateNoteShare)
+			noteShares.DELETE("/:principalId", assetHandler.RevokeNoteShare)
 		}
+
+		assetRouter.GET("/shared-with-me", assetHandler.GetSharedWithMe)
+
+		// Public link routes
+		folders.POST("/:folderId/public-links", publicLinkHandler.CreateFolderLink)
+		folders.GET("/:folderId/public-links", publicLinkHandler.GetFolderLinks)
+		notes.POST("/:noteId/public-links", publicLinkHandler.CreateNoteLink)
+		notes.GET("/:noteId/public-links", publicLinkHandler.GetNoteLinks)
+		assetRouter.DELETE("/public-links/:linkId", publicLinkHandler.RevokeLink)
+	}
+
+	// Anonymous read-only access through public links
+	publicLinks := publicRouter.Group("/links/:token")
+	{
+		publicLinks.GET("", publicLinkHandler.OpenLink)
+		publicLinks.GET("/folders/:folderId", publicLinkHandler.OpenLinkFolder)
+		publicLinks.GET("/notes/:noteId", publicLinkHandler.OpenLinkNote)
 	}
 
 	managerRouter := router.Group("/manager")
 	{
 		managerRouter.GET("/teams/:teamId/assets", assetHandler.GetTeamAssets)
+		managerRouter.GET("/teams/:teamId/trash", assetHandler.GetTeamTrash)
 		managerRouter.GET("/users/:userId/assets", assetHandler.GetUserAssets)
 	}
 }
+
+// envMegabytes reads a size in megabytes from the environment and returns it in bytes
+func envMegabytes(name string, fallback int64) int64 {
+	mb, err := strconv.ParseInt(os.Getenv(name), 10, 64)
+	if err != nil || mb <= 0 {
+		mb = fallback
+	}
+	return mb << 20
+}
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"go_service/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GroupRoutes sets up routes for team-scoped user groups
+func GroupRoutes(rg *gin.RouterGroup, h *handlers.GroupHandler) {
+	groups := rg.Group("/teams/:teamId/groups")
+	{
+		groups.POST("", h.CreateGroup)
+		groups.GET("", h.GetTeamGroups)
+		groups.PUT("/:groupId", h.UpdateGroup)
+		groups.DELETE("/:groupId", h.DeleteGroup)
+		groups.POST("/:groupId/members", h.AddGroupMembers)
+		groups.DELETE("/:groupId/members/:userId", h.RemoveGroupMember)
+	}
+}
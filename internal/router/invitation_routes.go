@@ -0,0 +1,23 @@
+package router
+
+import (
+	"go_service/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvitationRoutes sets up routes for team invitations
+func InvitationRoutes(rg *gin.RouterGroup, h *handlers.InvitationHandler) {
+	teams := rg.Group("/teams")
+	{
+		teams.POST("/:teamId/invitations", h.InviteMember)
+		teams.GET("/:teamId/invitations", h.GetTeamInvitations)
+	}
+
+	invitations := rg.Group("/invitations")
+	{
+		invitations.GET("", h.GetMyInvitations)
+		invitations.POST("/:token/accept", h.AcceptInvitation)
+		invitations.POST("/:token/decline", h.DeclineInvitation)
+	}
+}
@@ -1,6 +1,9 @@
 package router
 
 import (
+	"context"
+	"time"
+
 	"go_service/internal/handlers"
 	"go_service/internal/middleware"
 	"go_service/internal/repositories"
@@ -13,16 +16,28 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupRouter(router *gin.Engine, db *gorm.DB, producer *kafka.Producer, redis_client *redisclient.TeamCache) {
+func SetupRouter(router *gin.Engine, db *gorm.DB, producer *kafka.Producer, redis_client *redisclient.TeamCache, changes *redisclient.ChangeFeed) {
 	//Repositories
 	teamRepo := repositories.NewTeamRepository(db)
+	invitationRepo := repositories.NewInvitationRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	groupRepo := repositories.NewGroupRepository(db)
 
 	//Services
 	teamService := services.NewTeamService(teamRepo, producer, redis_client)
+	invitationService := services.NewInvitationService(invitationRepo, teamRepo, producer)
+	tagService := services.NewTagService(tagRepo, teamRepo)
+	groupService := services.NewGroupService(groupRepo, teamRepo)
+
+	// Background jobs
+	go invitationService.RunExpiryCleanup(context.Background(), time.Hour)
 
 	// Handlers
 	teamHandler := handlers.NewTeamHandler(teamService)
 	importHandler := handlers.NewImportHandler()
+	invitationHandler := handlers.NewInvitationHandler(invitationService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	groupHandler := handlers.NewGroupHandler(groupService)
 
 	//v1 api
 	v1 := router.Group("/api/v1")
@@ -38,7 +53,10 @@ func SetupRouter(router *gin.Engine, db *gorm.DB, producer *kafka.Producer, redi
 	protectedRoutes.Use(middleware.AuthMiddleware(db))
 
 	// Set up all routes
-	AssetRoutes(protectedRoutes, db)
+	AssetRoutes(protectedRoutes, publicRoutes, db, producer, changes)
 	TeamRoutes(protectedRoutes, teamHandler)
+	InvitationRoutes(protectedRoutes, invitationHandler)
+	TagRoutes(protectedRoutes, tagHandler)
+	GroupRoutes(protectedRoutes, groupHandler)
 	ImportRoutes(protectedRoutes, importHandler)
 }
@@ -0,0 +1,20 @@
+package router
+
+import (
+	"go_service/internal/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagRoutes sets up routes for team-scoped tags
+func TagRoutes(rg *gin.RouterGroup, h *handlers.TagHandler) {
+	tags := rg.Group("/teams/:teamId/tags")
+	{
+		tags.POST("", h.CreateTag)
+		tags.GET("", h.GetTeamTags)
+		tags.PUT("/:tagId", h.UpdateTag)
+		tags.DELETE("/:tagId", h.DeleteTag)
+	}
+
+	rg.GET("/manager/teams/:teamId/tags", h.GetTagCounts)
+}
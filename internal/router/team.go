@@ -11,14 +11,27 @@ func TeamRoutes(rg *gin.RouterGroup, h *handlers.TeamHandler) {
 	teams := rg.Group("/teams")
 	{
 		teams.POST("", h.CreateTeam)
-		teams.POST("/:teamId/members", h.AddMemberToTeam)
+		teams.GET("", h.ListTeams)
+		teams.GET("/:teamId", h.GetTeam)
+		teams.PUT("/:teamId", h.UpdateTeam)
+		teams.DELETE("/:teamId", h.DeleteTeam)
+		teams.POST("/:teamId/archive", h.ArchiveTeam)
+		teams.POST("/:teamId/unarchive", h.UnarchiveTeam)
 		teams.GET("/:teamId/members", h.GetTeamMembers)
 		teams.DELETE("/:teamId/members/:memberId", h.RemoveMember)
-		// teams.POST("/:teamId/managers", h.AddManagerToTeam)
+		teams.POST("/:teamId/managers", h.AddManagerToTeam)
 		teams.DELETE("/:teamId/managers/:managerId", h.RemoveManagerFromTeam)
+		teams.POST("/:teamId/managers/:managerId/demote", h.DemoteManager)
+		teams.PUT("/:teamId/owner", h.TransferOwnership)
 		// teams.GET("/:teamId/assets", h.GetTeamAssets)
 	}
 
+	// Admin overrides
+	admin := rg.Group("/admin")
+	{
+		admin.PUT("/teams/:teamId/owner", h.ForceTransferOwnership)
+	}
+
 	// User assets route - manager only
 	// users := rg.Group("/users")
 	// {
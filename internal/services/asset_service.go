@@ -2,10 +2,18 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"go_service/internal/dto"
 	"go_service/internal/models"
+	"go_service/internal/policy"
 	"go_service/internal/repositories"
+	"go_service/pkg/kafka"
+	"go_service/pkg/redisclient"
+	"go_service/pkg/textdiff"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,30 +23,139 @@ import (
 type IAssetService interface {
 	CreateFolder(ctx context.Context, req *dto.CreateFolderRequest, ownerID uuid.UUID) (*models.Folder, error)
 	GetFolder(ctx context.Context, folderID, userID uuid.UUID) (*models.Folder, error)
-	UpdateFolder(ctx context.Context, folderID, userID uuid.UUID, req *dto.UpdateFolderRequest) (*models.Folder, error)
+	UpdateFolder(ctx context.Context, folderID, userID uuid.UUID, req *dto.UpdateFolderRequest, expectedVersion int) (*models.Folder, error)
+	PatchFolder(ctx context.Context, folderID, userID uuid.UUID, patch *dto.FolderPatch, expectedVersion int) (*models.Folder, error)
 	DeleteFolder(ctx context.Context, folderID, userID uuid.UUID) error
+	GetFolderTree(ctx context.Context, folderID, userID uuid.UUID, depth int) (*dto.FolderNode, error)
+	MoveFolder(ctx context.Context, folderID, userID uuid.UUID, req *dto.MoveFolderRequest) (*models.Folder, error)
 
 	CreateNote(ctx context.Context, folderID, ownerID uuid.UUID, req *dto.CreateNoteRequest) (*models.Note, error)
 	GetNote(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, error)
-	UpdateNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.UpdateNoteRequest) (*models.Note, error)
+	UpdateNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.UpdateNoteRequest, expectedVersion int) (*models.Note, error)
+	PatchNote(ctx context.Context, noteID, userID uuid.UUID, patch *dto.NotePatch, expectedVersion int) (*models.Note, error)
 	DeleteNote(ctx context.Context, noteID, userID uuid.UUID) error
-
-	ShareResource(ctx context.Context, resourceID, ownerID uuid.UUID, resourceType string, req *dto.ShareRequest) error
-	RevokeShare(ctx context.Context, resourceID, ownerID, targetUserID uuid.UUID, resourceType string) error
-
-	GetTeamAssets(ctx context.Context, teamID, userID uuid.UUID) ([]models.Folder, error)
-	GetUserAssets(ctx context.Context, targetUserID, currentUserID uuid.UUID) ([]models.Folder, error)
+	MoveNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.MoveNoteRequest) (*models.Note, error)
+	CopyNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.CopyNoteRequest) (*models.Note, error)
+
+	GetNoteRevisions(ctx context.Context, noteID, userID uuid.UUID) ([]models.NoteRevision, error)
+	GetNoteRevision(ctx context.Context, noteID, userID uuid.UUID, revision int) (*models.NoteRevision, error)
+	DiffNoteRevisions(ctx context.Context, noteID, userID uuid.UUID, from, to int) (*dto.RevisionDiff, error)
+	RestoreNoteRevision(ctx context.Context, noteID, userID uuid.UUID, revision int) (*models.Note, error)
+
+	ShareResource(ctx context.Context, resourceID, ownerID uuid.UUID, resourceType string, req *dto.ShareRequest) (*dto.ShareResult, error)
+	UpdateSharePermission(ctx context.Context, resourceID, ownerID uuid.UUID, resourceType, principalType string, principalID uuid.UUID, req *dto.UpdateShareRequest) (*models.Share, error)
+	RevokeShare(ctx context.Context, resourceID, ownerID uuid.UUID, resourceType, principalType string, principalID uuid.UUID) error
+	GetResourceShares(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) ([]dto.ShareEntry, error)
+	GetSharedWithMe(ctx context.Context, userID uuid.UUID, req *dto.SharedWithMeRequest) (*dto.Page[dto.SharedItem], error)
+	TransferFolder(ctx context.Context, folderID, userID uuid.UUID, req *dto.TransferAssetRequest) (*models.Folder, error)
+	TransferNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.TransferAssetRequest) (*models.Note, error)
+
+	SearchNotes(ctx context.Context, userID uuid.UUID, req *dto.SearchNotesRequest) (*dto.NoteSearchResponse, error)
+
+	GetNoteTags(ctx context.Context, noteID, userID uuid.UUID) ([]models.Tag, error)
+	AttachNoteTag(ctx context.Context, noteID, userID uuid.UUID, req *dto.AttachTagRequest) ([]models.Tag, error)
+	DetachNoteTag(ctx context.Context, noteID, tagID, userID uuid.UUID) error
+	GetFolderTags(ctx context.Context, folderID, userID uuid.UUID) ([]models.Tag, error)
+	AttachFolderTag(ctx context.Context, folderID, userID uuid.UUID, req *dto.AttachTagRequest) ([]models.Tag, error)
+	DetachFolderTag(ctx context.Context, folderID, tagID, userID uuid.UUID) error
+	GetTaggedAssets(ctx context.Context, userID uuid.UUID, req *dto.TaggedAssetsRequest) (*dto.TaggedAssetsResponse, error)
+
+	GetMyTrash(ctx context.Context, userID uuid.UUID) (*dto.TrashResponse, error)
+	GetTeamTrash(ctx context.Context, teamID, userID uuid.UUID) (*dto.TrashResponse, error)
+	RestoreFolder(ctx context.Context, folderID, userID uuid.UUID) (*models.Folder, error)
+	RestoreNote(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, error)
+	RunTrashPurge(ctx context.Context, interval, retention time.Duration)
+	RunShareExpiry(ctx context.Context, interval time.Duration)
+
+	GetTeamAssets(ctx context.Context, teamID, userID uuid.UUID, req *dto.AssetListRequest) (*dto.Page[models.Folder], error)
+	GetUserAssets(ctx context.Context, targetUserID, currentUserID uuid.UUID, req *dto.AssetListRequest) (*dto.Page[dto.TeamAssets], error)
 }
 
 type AssetService struct {
-	assetRepo repositories.IAssetRepository
-	teamRepo  repositories.ITeamRepository
+	assetRepo   repositories.IAssetRepository
+	teamRepo    repositories.ITeamRepository
+	tagRepo     repositories.ITagRepository
+	groupRepo   repositories.IGroupRepository
+	userService *UserService
+	producer    *kafka.Producer
+	changes     *redisclient.ChangeFeed
+	policy      *policy.Engine
 }
 
-func NewAssetService(assetRepo repositories.IAssetRepository, teamRepo repositories.ITeamRepository) *AssetService {
+func NewAssetService(assetRepo repositories.IAssetRepository, teamRepo repositories.ITeamRepository, tagRepo repositories.ITagRepository, groupRepo repositories.IGroupRepository, producer *kafka.Producer, changes *redisclient.ChangeFeed) *AssetService {
 	return &AssetService{
-		assetRepo: assetRepo,
-		teamRepo:  teamRepo,
+		assetRepo:   assetRepo,
+		teamRepo:    teamRepo,
+		tagRepo:     tagRepo,
+		groupRepo:   groupRepo,
+		userService: NewUserService(),
+		producer:    producer,
+		changes:     changes,
+		policy:      policy.NewEngine(teamRepo, assetRepo),
+	}
+}
+
+// ensureTeamWritable rejects asset writes on archived teams
+func (s *AssetService) ensureTeamWritable(ctx context.Context, teamID uuid.UUID) error {
+	team, err := s.teamRepo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if team.Archived {
+		return errors.New("team is archived and read-only")
+	}
+	return nil
+}
+
+// publishNoteChange pushes a change to the streams of a note and of its folder
+func (s *AssetService) publishNoteChange(ctx context.Context, changeType string, note *models.Note, actorID uuid.UUID) {
+	s.publishChange(ctx, redisclient.Change{
+		Type:         changeType,
+		ResourceType: "note",
+		ResourceID:   note.ID,
+		FolderID:     &note.FolderID,
+		ActorID:      actorID,
+		Version:      note.Version,
+	}, redisclient.NoteChannel(note.ID), redisclient.FolderChannel(note.FolderID))
+}
+
+// publishFolderChange pushes a change to the streams of a folder and of its parent
+func (s *AssetService) publishFolderChange(ctx context.Context, changeType string, folder *models.Folder, actorID uuid.UUID) {
+	channels := []string{redisclient.FolderChannel(folder.ID)}
+	if folder.ParentID != nil {
+		channels = append(channels, redisclient.FolderChannel(*folder.ParentID))
+	}
+	s.publishChange(ctx, redisclient.Change{
+		Type:         changeType,
+		ResourceType: "folder",
+		ResourceID:   folder.ID,
+		FolderID:     folder.ParentID,
+		ActorID:      actorID,
+		Version:      folder.Version,
+	}, channels...)
+}
+
+// publishShareChange tells the subscribers of a folder or note that who may see it has changed
+func (s *AssetService) publishShareChange(ctx context.Context, resourceID uuid.UUID, resourceType string, actorID uuid.UUID) {
+	channel := redisclient.FolderChannel(resourceID)
+	if resourceType == "note" {
+		channel = redisclient.NoteChannel(resourceID)
+	}
+	s.publishChange(ctx, redisclient.Change{
+		Type:         redisclient.ChangeShareChanged,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ActorID:      actorID,
+	}, channel)
+}
+
+// publishChange only costs subscribers a live update when it fails, so errors are logged
+func (s *AssetService) publishChange(ctx context.Context, change redisclient.Change, channels ...string) {
+	if s.changes == nil {
+		return
+	}
+	if err := s.changes.Publish(ctx, change, channels...); err != nil {
+		log.Printf("Failed to publish %s change for %s: %v", change.Type, change.ResourceID, err)
 	}
 }
 
@@ -49,21 +166,37 @@ func (s *AssetService) CreateFolder(ctx context.Context, req *dto.CreateFolderRe
 		return nil, err
 	}
 
-	// Check if user belongs to the team
-	isMember, err := s.teamRepo.IsUserInTeam(ctx, teamID, ownerID)
-	if err != nil {
+	if err := s.policy.Authorize(ctx, ownerID, policy.Create, policy.TeamResource(teamID)); err != nil {
 		return nil, err
 	}
 
-	if !isMember {
-		return nil, errors.New("user is not a member of this team")
+	if err := s.ensureTeamWritable(ctx, teamID); err != nil {
+		return nil, err
+	}
+
+	// Sub-folders need write access on their parent, which must live in the same team
+	var parentID *uuid.UUID
+	if req.ParentID != "" {
+		id, err := uuid.Parse(req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		parent, err := s.authorizeFolder(ctx, id, ownerID, policy.Write)
+		if err != nil {
+			return nil, err
+		}
+		if parent.TeamID != teamID {
+			return nil, errors.New("parent folder belongs to another team")
+		}
+		parentID = &parent.ID
 	}
 
 	folder := &models.Folder{
-		ID:      uuid.New(),
-		Name:    req.Name,
-		OwnerID: ownerID,
-		TeamID:  teamID,
+		ID:       uuid.New(),
+		Name:     req.Name,
+		ParentID: parentID,
+		OwnerID:  ownerID,
+		TeamID:   teamID,
 
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -72,12 +205,35 @@ func (s *AssetService) CreateFolder(ctx context.Context, req *dto.CreateFolderRe
 	if err := s.assetRepo.CreateFolder(ctx, folder); err != nil {
 		return nil, err
 	}
+	if folder.ParentID != nil {
+		s.publishFolderChange(ctx, redisclient.ChangeFolderCreated, folder, ownerID)
+	}
 
 	return folder, nil
 }
 
 // GetFolder retrieves a folder by ID if the user has access to it
 func (s *AssetService) GetFolder(ctx context.Context, folderID, userID uuid.UUID) (*models.Folder, error) {
+	folder, err := s.authorizeFolder(ctx, folderID, userID, policy.Read)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach breadcrumbs from the top-level folder
+	ancestors, err := s.assetRepo.GetFolderAncestors(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+	folder.Path = make([]models.FolderCrumb, 0, len(ancestors))
+	for _, ancestor := range ancestors {
+		folder.Path = append(folder.Path, models.FolderCrumb{ID: ancestor.ID, Name: ancestor.Name})
+	}
+
+	return folder, nil
+}
+
+// authorizeFolder loads a live folder and checks that the user may perform action on it
+func (s *AssetService) authorizeFolder(ctx context.Context, folderID, userID uuid.UUID, action policy.Action) (*models.Folder, error) {
 	folder, err := s.assetRepo.GetFolderByID(ctx, folderID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -86,106 +242,248 @@ func (s *AssetService) GetFolder(ctx context.Context, folderID, userID uuid.UUID
 		return nil, err
 	}
 
-	// Check if user is owner
-	if folder.OwnerID == userID {
-		return folder, nil
+	if err := s.policy.Authorize(ctx, userID, action, policy.FolderResource(folder)); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// authorizeNote loads a live note with its folder and checks that the user may perform action on it
+func (s *AssetService) authorizeNote(ctx context.Context, noteID, userID uuid.UUID, action policy.Action) (*models.Note, *models.Folder, error) {
+	note, err := s.assetRepo.GetNoteByID(ctx, noteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("note not found")
+		}
+		return nil, nil, err
+	}
+	folder, err := s.assetRepo.GetFolderByID(ctx, note.FolderID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Check if folder is shared with user
-	share, err := s.assetRepo.GetShare(ctx, folderID, userID, "folder")
-	if err == nil && share != nil {
-		return folder, nil
+	if err := s.policy.Authorize(ctx, userID, action, policy.NoteResource(note, folder)); err != nil {
+		return nil, nil, err
+	}
+	return note, folder, nil
+}
+
+// Depth limits for the folder tree endpoint
+const (
+	defaultFolderTreeDepth = 3
+	maxFolderTreeDepth     = 10
+)
+
+// GetFolderTree returns the folder with its sub-folders down to depth levels
+func (s *AssetService) GetFolderTree(ctx context.Context, folderID, userID uuid.UUID, depth int) (*dto.FolderNode, error) {
+	if depth <= 0 {
+		depth = defaultFolderTreeDepth
+	}
+	if depth > maxFolderTreeDepth {
+		depth = maxFolderTreeDepth
+	}
+
+	// Read access on the root covers the whole subtree
+	if _, err := s.GetFolder(ctx, folderID, userID); err != nil {
+		return nil, err
 	}
 
-	// Check if user is team manager
-	isManager, err := s.teamRepo.IsManager(ctx, folder.TeamID, userID)
+	folders, err := s.assetRepo.GetFolderSubtree(ctx, folderID, depth)
 	if err != nil {
 		return nil, err
 	}
-	if isManager {
-		return folder, nil
+	if len(folders) == 0 {
+		return nil, errors.New("folder not found")
+	}
+
+	nodes := make(map[uuid.UUID]*dto.FolderNode, len(folders))
+	for _, folder := range folders {
+		nodes[folder.ID] = &dto.FolderNode{Folder: folder, Children: []*dto.FolderNode{}}
+	}
+	// Folders come ordered by depth, so every parent is already in the map
+	for _, folder := range folders {
+		if folder.ID == folderID || folder.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*folder.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[folder.ID])
+		}
 	}
 
-	return nil, errors.New("you don't have access to this folder")
+	return nodes[folderID], nil
 }
 
-// UpdateFolder updates a folder if the user has write access
-func (s *AssetService) UpdateFolder(ctx context.Context, folderID, userID uuid.UUID, req *dto.UpdateFolderRequest) (*models.Folder, error) {
-	folder, err := s.assetRepo.GetFolderByID(ctx, folderID)
+// MoveFolder re-parents a folder inside its team, refusing moves that would create a cycle
+func (s *AssetService) MoveFolder(ctx context.Context, folderID, userID uuid.UUID, req *dto.MoveFolderRequest) (*models.Folder, error) {
+	folder, err := s.authorizeFolder(ctx, folderID, userID, policy.Write)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("folder not found")
+		return nil, err
+	}
+
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNewParent(ctx, folder, req.ParentID, userID); err != nil {
+		return nil, err
+	}
+
+	previousParentID := folder.ParentID
+	folder.ParentID = req.ParentID
+	folder.UpdatedAt = time.Now()
+
+	if err := s.assetRepo.UpdateFolder(ctx, folder, 0); err != nil {
+		return nil, err
+	}
+
+	s.publishFolderChange(ctx, redisclient.ChangeFolderMoved, folder, userID)
+	if previousParentID != nil {
+		// The old parent's stream also learns that the folder left
+		s.publishChange(ctx, redisclient.Change{
+			Type:         redisclient.ChangeFolderMoved,
+			ResourceType: "folder",
+			ResourceID:   folder.ID,
+			FolderID:     folder.ParentID,
+			ActorID:      userID,
+			Version:      folder.Version,
+		}, redisclient.FolderChannel(*previousParentID))
+	}
+
+	return folder, nil
+}
+
+// checkNewParent validates a move under parentID (nil for the top level)
+func (s *AssetService) checkNewParent(ctx context.Context, folder *models.Folder, parentID *uuid.UUID, userID uuid.UUID) error {
+	if parentID == nil {
+		return nil
+	}
+
+	parent, err := s.authorizeFolder(ctx, *parentID, userID, policy.Write)
+	if err != nil {
+		return err
+	}
+	if parent.TeamID != folder.TeamID {
+		return errors.New("cannot move a folder to another team")
+	}
+
+	// The new parent must not be the folder itself or one of its descendants
+	ancestors, err := s.assetRepo.GetFolderAncestors(ctx, parent.ID)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor.ID == folder.ID {
+			return errors.New("cannot move a folder into itself or one of its sub-folders")
 		}
+	}
+	return nil
+}
+
+// PatchFolder applies a JSON merge patch to a folder the user can edit.
+// A non-zero expectedVersion makes the update fail with a VersionConflictError when the folder has changed.
+func (s *AssetService) PatchFolder(ctx context.Context, folderID, userID uuid.UUID, patch *dto.FolderPatch, expectedVersion int) (*models.Folder, error) {
+	folder, err := s.authorizeFolder(ctx, folderID, userID, policy.Write)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
 		return nil, err
 	}
+	if expectedVersion > 0 && folder.Version != expectedVersion {
+		return nil, &VersionConflictError{CurrentVersion: folder.Version}
+	}
+	if patch.IsEmpty() {
+		return folder, nil
+	}
+
+	if patch.Name != nil {
+		folder.Name = *patch.Name
+	}
+	if patch.SetParent {
+		if err := s.checkNewParent(ctx, folder, patch.ParentID, userID); err != nil {
+			return nil, err
+		}
+		folder.ParentID = patch.ParentID
+	}
+	folder.UpdatedAt = time.Now()
 
-	// Check if user is owner
-	if folder.OwnerID != userID {
-		// Check if folder is shared with write permissions
-		share, err := s.assetRepo.GetShare(ctx, folderID, userID, "folder")
-		if err != nil || share == nil || share.Permission != "write" {
-			return nil, errors.New("you don't have write access to this folder")
+	if err := s.assetRepo.UpdateFolder(ctx, folder, expectedVersion); err != nil {
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return nil, s.folderVersionConflict(ctx, folderID)
 		}
+		return nil, err
+	}
+
+	s.publishFolderChange(ctx, redisclient.ChangeFolderUpdated, folder, userID)
+	return folder, nil
+}
+
+// UpdateFolder updates a folder if the user has write access.
+// A non-zero expectedVersion makes the update fail with a VersionConflictError when the folder has changed.
+func (s *AssetService) UpdateFolder(ctx context.Context, folderID, userID uuid.UUID, req *dto.UpdateFolderRequest, expectedVersion int) (*models.Folder, error) {
+	folder, err := s.authorizeFolder(ctx, folderID, userID, policy.Write)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update folder fields
-	if req.Name != "" {
-		folder.Name = req.Name
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return nil, err
+	}
+
+	if expectedVersion > 0 && folder.Version != expectedVersion {
+		return nil, &VersionConflictError{CurrentVersion: folder.Version}
 	}
 
+	// Full replacement of the editable fields
+	folder.Name = req.Name
 	folder.UpdatedAt = time.Now()
 
-	if err := s.assetRepo.UpdateFolder(ctx, folder); err != nil {
+	if err := s.assetRepo.UpdateFolder(ctx, folder, expectedVersion); err != nil {
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return nil, s.folderVersionConflict(ctx, folderID)
+		}
 		return nil, err
 	}
 
+	s.publishFolderChange(ctx, redisclient.ChangeFolderUpdated, folder, userID)
 	return folder, nil
 }
 
-// DeleteFolder deletes a folder if the user is the owner
+// DeleteFolder moves a folder to the trash if the user is the owner or a team manager
 func (s *AssetService) DeleteFolder(ctx context.Context, folderID, userID uuid.UUID) error {
-	folder, err := s.assetRepo.GetFolderByID(ctx, folderID)
+	folder, err := s.authorizeFolder(ctx, folderID, userID, policy.Delete)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("folder not found")
-		}
 		return err
 	}
 
-	// Only the owner can delete the folder
-	if folder.OwnerID != userID {
-		isManager, err := s.teamRepo.IsManager(ctx, folder.TeamID, userID)
-		if err != nil {
-			return err
-		}
-		if !isManager {
-			return errors.New("only the folder owner or team manager can delete this folder")
-		}
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return err
 	}
 
-	return s.assetRepo.DeleteFolder(ctx, folderID)
+	if err := s.assetRepo.DeleteFolder(ctx, folderID, userID); err != nil {
+		return err
+	}
+
+	s.publishFolderChange(ctx, redisclient.ChangeFolderDeleted, folder, userID)
+	return nil
 }
 
 // CreateNote creates a new note inside a folder
 func (s *AssetService) CreateNote(ctx context.Context, folderID, ownerID uuid.UUID, req *dto.CreateNoteRequest) (*models.Note, error) {
-	// Check if folder exists and user has access to it
-	folder, err := s.GetFolder(ctx, folderID, ownerID)
+	folder, err := s.authorizeFolder(ctx, folderID, ownerID, policy.Write)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if user has write access to folder
-	if folder.OwnerID != ownerID {
-		share, err := s.assetRepo.GetShare(ctx, folderID, ownerID, "folder")
-		if err != nil || share == nil || share.Permission != "write" {
-			return nil, errors.New("you don't have write access to this folder")
-		}
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return nil, err
 	}
 
 	note := &models.Note{
 		ID:        uuid.New(),
 		FolderID:  folderID,
 		OwnerID:   ownerID,
+		TeamID:    folder.TeamID,
 		Title:     req.Title,
 		Content:   req.Content,
 		CreatedAt: time.Now(),
@@ -196,273 +494,1213 @@ func (s *AssetService) CreateNote(ctx context.Context, folderID, ownerID uuid.UU
 		return nil, err
 	}
 
+	s.publishNoteChange(ctx, redisclient.ChangeNoteCreated, note, ownerID)
+
 	return note, nil
 }
 
 // GetNote retrieves a note by ID if the user has access to it
 func (s *AssetService) GetNote(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, error) {
-	note, err := s.assetRepo.GetNoteByID(ctx, noteID)
+	note, _, err := s.authorizeNote(ctx, noteID, userID, policy.Read)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("note not found")
-		}
 		return nil, err
 	}
+	return note, nil
+}
 
-	// Check if user is owner
-	if note.OwnerID == userID {
-		return note, nil
-	}
-
-	// Check if note is shared with user
-	share, err := s.assetRepo.GetShare(ctx, noteID, userID, "note")
-	if err == nil && share != nil {
-		return note, nil
-	}
-
-	// Check if parent folder is shared with user
-	folderShare, err := s.assetRepo.GetShare(ctx, note.FolderID, userID, "folder")
-	if err == nil && folderShare != nil {
-		return note, nil
-	}
-
-	// Get folder to check team manager access
-	folder, err := s.assetRepo.GetFolderByID(ctx, note.FolderID)
+// UpdateNote updates a note if the user has write access
+func (s *AssetService) UpdateNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.UpdateNoteRequest, expectedVersion int) (*models.Note, error) {
+	note, err := s.getWritableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
+	if expectedVersion > 0 && note.Version != expectedVersion {
+		return nil, &VersionConflictError{CurrentVersion: note.Version}
+	}
 
-	// Check if user is team manager
-	isManager, err := s.teamRepo.IsManager(ctx, folder.TeamID, userID)
-	if err != nil {
+	// Full replacement of the editable fields
+	note.Title = req.Title
+	note.Content = req.Content
+	note.UpdatedAt = time.Now()
+
+	if err := s.assetRepo.UpdateNote(ctx, note, userID, expectedVersion); err != nil {
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return nil, s.noteVersionConflict(ctx, noteID)
+		}
 		return nil, err
 	}
-	if isManager {
-		return note, nil
-	}
 
-	return nil, errors.New("you don't have access to this note")
+	s.publishNoteChange(ctx, redisclient.ChangeNoteUpdated, note, userID)
+	return note, nil
 }
 
-// UpdateNote updates a note if the user has write access
-func (s *AssetService) UpdateNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.UpdateNoteRequest) (*models.Note, error) {
-	note, err := s.assetRepo.GetNoteByID(ctx, noteID)
+// PatchNote applies a JSON merge patch to a note the user can edit; a null content clears it.
+// A non-zero expectedVersion makes the update fail with a VersionConflictError when the note has changed.
+func (s *AssetService) PatchNote(ctx context.Context, noteID, userID uuid.UUID, patch *dto.NotePatch, expectedVersion int) (*models.Note, error) {
+	note, err := s.getWritableNote(ctx, noteID, userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("note not found")
-		}
 		return nil, err
 	}
-
-	// Check permissions
-	if note.OwnerID != userID {
-		// Check direct note share
-		noteShare, err := s.assetRepo.GetShare(ctx, noteID, userID, "note")
-		if err == nil && noteShare != nil && noteShare.Permission == "write" {
-			// User has direct write permission on note
-		} else {
-			// Check folder share
-			folderShare, err := s.assetRepo.GetShare(ctx, note.FolderID, userID, "folder")
-			if err != nil || folderShare == nil || folderShare.Permission != "write" {
-				return nil, errors.New("you don't have write access to this note")
-			}
-		}
+	if expectedVersion > 0 && note.Version != expectedVersion {
+		return nil, &VersionConflictError{CurrentVersion: note.Version}
+	}
+	if patch.IsEmpty() {
+		return note, nil
 	}
 
-	// Update note fields
-	if req.Title != "" {
-		note.Title = req.Title
+	if patch.Title != nil {
+		note.Title = *patch.Title
 	}
-	if req.Content != "" {
-		note.Content = req.Content
+	if patch.Content != nil {
+		note.Content = *patch.Content
 	}
 	note.UpdatedAt = time.Now()
 
-	if err := s.assetRepo.UpdateNote(ctx, note); err != nil {
+	if err := s.assetRepo.UpdateNote(ctx, note, userID, expectedVersion); err != nil {
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			return nil, s.noteVersionConflict(ctx, noteID)
+		}
 		return nil, err
 	}
 
+	s.publishNoteChange(ctx, redisclient.ChangeNoteUpdated, note, userID)
 	return note, nil
 }
 
-// DeleteNote deletes a note if the user is the owner or has appropriate permissions
-func (s *AssetService) DeleteNote(ctx context.Context, noteID, userID uuid.UUID) error {
+// VersionConflictError reports a stale precondition together with the version currently stored
+type VersionConflictError struct {
+	CurrentVersion int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("resource has been modified, current version is %d", e.CurrentVersion)
+}
+
+// noteVersionConflict builds the conflict error for a note that changed under a guarded update
+func (s *AssetService) noteVersionConflict(ctx context.Context, noteID uuid.UUID) error {
 	note, err := s.assetRepo.GetNoteByID(ctx, noteID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("note not found")
-		}
 		return err
 	}
-
-	// Only the owner or folder owner can delete the note
-	if note.OwnerID != userID {
-		// Check if user is folder owner
-		folder, err := s.assetRepo.GetFolderByID(ctx, note.FolderID)
-		if err != nil {
-			return err
-		}
-
-		if folder.OwnerID != userID {
-			// Check if user is team manager
-			isManager, err := s.teamRepo.IsManager(ctx, folder.TeamID, userID)
-			if err != nil {
-				return err
-			}
-			if !isManager {
-				return errors.New("only the note owner, folder owner, or team manager can delete this note")
-			}
-		}
-	}
-
-	return s.assetRepo.DeleteNote(ctx, noteID)
+	return &VersionConflictError{CurrentVersion: note.Version}
 }
 
-// ShareResource shares a resource (folder or note) with another user
-func (s *AssetService) ShareResource(ctx context.Context, resourceID, ownerID uuid.UUID, resourceType string, req *dto.ShareRequest) error {
-	targetUserID, err := uuid.Parse(req.UserID)
+// folderVersionConflict builds the conflict error for a folder that changed under a guarded update
+func (s *AssetService) folderVersionConflict(ctx context.Context, folderID uuid.UUID) error {
+	folder, err := s.assetRepo.GetFolderByID(ctx, folderID)
 	if err != nil {
 		return err
 	}
+	return &VersionConflictError{CurrentVersion: folder.Version}
+}
 
-	// Verify resource exists and user is the owner
-	var resourceOwnerID uuid.UUID
-	var teamID uuid.UUID
-
-	if resourceType == "folder" {
-		folder, err := s.assetRepo.GetFolderByID(ctx, resourceID)
-		if err != nil {
-			return errors.New("folder not found")
-		}
-		resourceOwnerID = folder.OwnerID
-		teamID = folder.TeamID
-	} else if resourceType == "note" {
-		note, err := s.assetRepo.GetNoteByID(ctx, resourceID)
-		if err != nil {
-			return errors.New("note not found")
-		}
-		resourceOwnerID = note.OwnerID
-
-		// Get folder to check team manager access
-		folder, err := s.assetRepo.GetFolderByID(ctx, note.FolderID)
-		if err != nil {
-			return err
-		}
-		teamID = folder.TeamID
-	} else {
-		return errors.New("invalid resource type")
-	}
-
-	// Check if user is owner or team manager
-	if resourceOwnerID != ownerID {
-		isManager, err := s.teamRepo.IsManager(ctx, teamID, ownerID)
-		if err != nil {
-			return err
-		}
-		if !isManager {
-			return errors.New("only resource owner or team manager can share resources")
-		}
-	}
-
-	// Check if target user is a member of the team
-	isMember, err := s.teamRepo.IsUserInTeam(ctx, teamID, targetUserID)
+// getWritableNote loads a note the user may edit, rejecting notes of archived teams
+func (s *AssetService) getWritableNote(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, error) {
+	note, folder, err := s.authorizeNote(ctx, noteID, userID, policy.Write)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if !isMember {
-		return errors.New("can only share with team members")
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return nil, err
 	}
+	return note, nil
+}
 
-	// Create share
-	share := &models.Share{
-		ResourceID:   resourceID,
-		ResourceType: resourceType,
-		UserID:       targetUserID,
-		Permission:   req.Permission,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+// GetNoteRevisions lists the history of a note the user can read
+func (s *AssetService) GetNoteRevisions(ctx context.Context, noteID, userID uuid.UUID) ([]models.NoteRevision, error) {
+	if _, err := s.GetNote(ctx, noteID, userID); err != nil {
+		return nil, err
 	}
-
-	return s.assetRepo.CreateShare(ctx, share)
+	return s.assetRepo.GetNoteRevisions(ctx, noteID)
 }
 
-// RevokeShare revokes sharing permissions for a resource
-func (s *AssetService) RevokeShare(ctx context.Context, resourceID, ownerID, targetUserID uuid.UUID, resourceType string) error {
-	// Verify resource exists and user is the owner
-	var resourceOwnerID uuid.UUID
-	var teamID uuid.UUID
-
-	if resourceType == "folder" {
-		folder, err := s.assetRepo.GetFolderByID(ctx, resourceID)
-		if err != nil {
-			return errors.New("folder not found")
-		}
-		resourceOwnerID = folder.OwnerID
-		teamID = folder.TeamID
-	} else if resourceType == "note" {
-		note, err := s.assetRepo.GetNoteByID(ctx, resourceID)
-		if err != nil {
-			return errors.New("note not found")
-		}
-		resourceOwnerID = note.OwnerID
-
-		// Get folder to check team manager access
-		folder, err := s.assetRepo.GetFolderByID(ctx, note.FolderID)
-		if err != nil {
-			return err
-		}
-		teamID = folder.TeamID
-	} else {
-		return errors.New("invalid resource type")
+// GetNoteRevision returns a single revision of a note the user can read
+func (s *AssetService) GetNoteRevision(ctx context.Context, noteID, userID uuid.UUID, revision int) (*models.NoteRevision, error) {
+	if _, err := s.GetNote(ctx, noteID, userID); err != nil {
+		return nil, err
 	}
 
-	// Check if user is owner or team manager
-	if resourceOwnerID != ownerID {
-		isManager, err := s.teamRepo.IsManager(ctx, teamID, ownerID)
-		if err != nil {
-			return err
-		}
-		if !isManager {
-			return errors.New("only resource owner or team manager can revoke shares")
+	noteRevision, err := s.assetRepo.GetNoteRevision(ctx, noteID, revision)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
 		}
+		return nil, err
 	}
-
-	// Delete share
-	return s.assetRepo.DeleteShare(ctx, resourceID, targetUserID, resourceType)
+	return noteRevision, nil
 }
 
-// GetTeamAssets retrieves all folders belonging to a team if the user is a manager
-func (s *AssetService) GetTeamAssets(ctx context.Context, teamID, userID uuid.UUID) ([]models.Folder, error) {
-	// Check if user is a team manager
-	isManager, err := s.teamRepo.IsManager(ctx, teamID, userID)
+// DiffNoteRevisions returns a line diff of the title and content between two revisions
+func (s *AssetService) DiffNoteRevisions(ctx context.Context, noteID, userID uuid.UUID, from, to int) (*dto.RevisionDiff, error) {
+	fromRevision, err := s.GetNoteRevision(ctx, noteID, userID, from)
 	if err != nil {
 		return nil, err
 	}
-	if !isManager {
-		return nil, errors.New("only team managers can view all team assets")
+	toRevision, err := s.assetRepo.GetNoteRevision(ctx, noteID, to)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
+		}
+		return nil, err
 	}
 
-	// Retrieve team assets
-	return s.assetRepo.GetTeamAssets(ctx, teamID)
+	return &dto.RevisionDiff{
+		NoteID:   noteID,
+		From:     fromRevision.Revision,
+		To:       toRevision.Revision,
+		FromBy:   fromRevision.AuthorID,
+		ToBy:     toRevision.AuthorID,
+		Title:    textdiff.Lines(fromRevision.Title, toRevision.Title),
+		Content:  textdiff.Lines(fromRevision.Content, toRevision.Content),
+		FromDate: fromRevision.CreatedAt,
+		ToDate:   toRevision.CreatedAt,
+	}, nil
 }
 
-// GetUserAssets retrieves all folders belonging to a specific user if the requester is a manager
-func (s *AssetService) GetUserAssets(ctx context.Context, targetUserID, currentUserID uuid.UUID) ([]models.Folder, error) {
-	// Get user's team ID
-	teamID, err := s.teamRepo.GetUserTeamID(ctx, targetUserID)
+// RestoreNoteRevision copies an old revision back onto the note, recorded as a new revision
+func (s *AssetService) RestoreNoteRevision(ctx context.Context, noteID, userID uuid.UUID, revision int) (*models.Note, error) {
+	note, err := s.getWritableNote(ctx, noteID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Check if requester is a manager of the user's team
-	isManager, err := s.teamRepo.IsManager(ctx, teamID, currentUserID)
+	noteRevision, err := s.assetRepo.GetNoteRevision(ctx, noteID, revision)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("revision not found")
+		}
+		return nil, err
+	}
+
+	note.Title = noteRevision.Title
+	note.Content = noteRevision.Content
+	note.UpdatedAt = time.Now()
+
+	if err := s.assetRepo.UpdateNote(ctx, note, userID, 0); err != nil {
+		return nil, err
+	}
+
+	s.publishNoteChange(ctx, redisclient.ChangeNoteUpdated, note, userID)
+	return note, nil
+}
+
+// DeleteNote moves a note to the trash if the user is its owner, the folder owner or a team manager
+func (s *AssetService) DeleteNote(ctx context.Context, noteID, userID uuid.UUID) error {
+	note, folder, err := s.authorizeNote(ctx, noteID, userID, policy.Delete)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return err
+	}
+
+	if err := s.assetRepo.DeleteNote(ctx, noteID, userID); err != nil {
+		return err
+	}
+
+	s.publishNoteChange(ctx, redisclient.ChangeNoteDeleted, note, userID)
+	return nil
+}
+
+// checkCrossTeamTransfer only lets managers of both teams move content between teams
+func (s *AssetService) checkCrossTeamTransfer(ctx context.Context, sourceTeamID, targetTeamID, userID uuid.UUID) error {
+	if sourceTeamID == targetTeamID {
+		return nil
+	}
+	for _, teamID := range []uuid.UUID{sourceTeamID, targetTeamID} {
+		allowed, err := s.policy.Can(ctx, userID, policy.Manage, policy.TeamResource(teamID))
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return errors.New("only managers of both teams can move notes between teams")
+		}
+	}
+	return nil
+}
+
+// MoveNote moves a note to another folder, keeping its owner, timestamps and shares
+func (s *AssetService) MoveNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.MoveNoteRequest) (*models.Note, error) {
+	targetFolderID, err := uuid.Parse(req.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.assetRepo.GetNoteByID(ctx, noteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("note not found")
+		}
+		return nil, err
+	}
+	if note.FolderID == targetFolderID {
+		return note, nil
+	}
+
+	// Write access is needed on both ends of the move
+	source, err := s.authorizeFolder(ctx, note.FolderID, userID, policy.Write)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.authorizeFolder(ctx, targetFolderID, userID, policy.Write)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCrossTeamTransfer(ctx, source.TeamID, target.TeamID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureTeamWritable(ctx, source.TeamID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureTeamWritable(ctx, target.TeamID); err != nil {
+		return nil, err
+	}
+
+	note.FolderID = target.ID
+	note.TeamID = target.TeamID
+
+	if err := s.assetRepo.MoveNote(ctx, note); err != nil {
+		return nil, err
+	}
+
+	s.publishNoteChange(ctx, redisclient.ChangeNoteMoved, note, userID)
+	// The source folder's stream also learns that the note left
+	s.publishChange(ctx, redisclient.Change{
+		Type:         redisclient.ChangeNoteMoved,
+		ResourceType: "note",
+		ResourceID:   note.ID,
+		FolderID:     &note.FolderID,
+		ActorID:      userID,
+		Version:      note.Version,
+	}, redisclient.FolderChannel(source.ID))
+
+	return note, nil
+}
+
+// CopyNote duplicates a readable note into a folder the user can write to; the copy belongs to the user
+func (s *AssetService) CopyNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.CopyNoteRequest) (*models.Note, error) {
+	targetFolderID, err := uuid.Parse(req.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	source, sourceFolder, err := s.authorizeNote(ctx, noteID, userID, policy.Read)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.authorizeFolder(ctx, targetFolderID, userID, policy.Write)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCrossTeamTransfer(ctx, sourceFolder.TeamID, target.TeamID, userID); err != nil {
+		return nil, err
+	}
+	if err := s.ensureTeamWritable(ctx, target.TeamID); err != nil {
+		return nil, err
+	}
+
+	title := req.Title
+	if title == "" {
+		title = source.Title
+	}
+
+	// Tags are team-scoped, so they only carry over within the same team
+	var tagIDs []uuid.UUID
+	if sourceFolder.TeamID == target.TeamID {
+		tags, err := s.tagRepo.GetNoteTags(ctx, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+	}
+
+	note := &models.Note{
+		FolderID:  target.ID,
+		OwnerID:   userID,
+		TeamID:    target.TeamID,
+		Title:     title,
+		Content:   source.Content,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := s.assetRepo.CreateNote(ctx, note, tagIDs...); err != nil {
+		return nil, err
+	}
+
+	s.publishNoteChange(ctx, redisclient.ChangeNoteCreated, note, userID)
+	return note, nil
+}
+
+// getShareTarget loads a folder or note and checks that userID may manage its shares
+func (s *AssetService) getShareTarget(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) (policy.Resource, error) {
+	switch resourceType {
+	case "folder":
+		folder, err := s.authorizeFolder(ctx, resourceID, userID, policy.Share)
+		if err != nil {
+			return policy.Resource{}, err
+		}
+		return policy.FolderResource(folder), nil
+	case "note":
+		note, folder, err := s.authorizeNote(ctx, resourceID, userID, policy.Share)
+		if err != nil {
+			return policy.Resource{}, err
+		}
+		return policy.NoteResource(note, folder), nil
+	}
+	return policy.Resource{}, errors.New("invalid resource type")
+}
+
+// checkGrantCeiling stops users from granting more access than they hold themselves
+func (s *AssetService) checkGrantCeiling(ctx context.Context, userID uuid.UUID, target policy.Resource, permission string) error {
+	level, err := s.policy.Level(ctx, userID, target)
+	if err != nil {
+		return err
+	}
+	if !level.Includes(models.AccessLevel(permission)) {
+		return fmt.Errorf("cannot grant %s access when you only hold %s", permission, level)
+	}
+	return nil
+}
+
+// sharePrincipal reads the principal of a share request; a bare userId shares with that user
+func sharePrincipal(req *dto.ShareRequest) (models.SharePrincipal, error) {
+	principal := models.SharePrincipal{Type: req.PrincipalType}
+	if principal.Type == "" {
+		principal.Type = models.PrincipalUser
+	}
+	rawID := req.PrincipalID
+	if rawID == "" && principal.Type == models.PrincipalUser {
+		rawID = req.UserID
+	}
+	if rawID == "" {
+		return principal, errors.New("principalId is required")
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return principal, err
+	}
+	principal.ID = id
+	return principal, nil
+}
+
+// checkSharePrincipal makes sure a principal belongs to the team owning the shared resource
+func (s *AssetService) checkSharePrincipal(ctx context.Context, teamID uuid.UUID, principal models.SharePrincipal) error {
+	switch principal.Type {
+	case models.PrincipalUser:
+		isMember, err := s.teamRepo.IsUserInTeam(ctx, teamID, principal.ID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return errors.New("can only share with team members")
+		}
+	case models.PrincipalTeam:
+		if principal.ID != teamID {
+			return errors.New("can only share with the team owning the resource")
+		}
+	case models.PrincipalGroup:
+		group, err := s.groupRepo.GetGroupByID(ctx, principal.ID)
+		if err != nil || group.TeamID != teamID {
+			return errors.New("group not found in this team")
+		}
+	default:
+		return errors.New("invalid principal type")
+	}
+	return nil
+}
+
+// ShareResource shares a resource (folder or note) with a user, a whole team or a group.
+// Sharing again with the same principal replaces the permission and expiry instead of adding a second share.
+func (s *AssetService) ShareResource(ctx context.Context, resourceID, ownerID uuid.UUID, resourceType string, req *dto.ShareRequest) (*dto.ShareResult, error) {
+	principal, err := sharePrincipal(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiresAt must be in the future")
+	}
+
+	target, err := s.getShareTarget(ctx, resourceID, ownerID, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureTeamWritable(ctx, target.TeamID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkSharePrincipal(ctx, target.TeamID, principal); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkGrantCeiling(ctx, ownerID, target, req.Permission); err != nil {
+		return nil, err
+	}
+
+	// Create the share, or change the permission of the existing one
+	share := &models.Share{
+		ResourceID:    resourceID,
+		ResourceType:  resourceType,
+		PrincipalType: principal.Type,
+		PrincipalID:   principal.ID,
+		Permission:    req.Permission,
+		ExpiresAt:     req.ExpiresAt,
+	}
+	change, err := s.assetRepo.UpsertShare(ctx, share)
+	if err != nil {
+		return nil, err
+	}
+	if change != repositories.ShareUnchanged {
+		s.publishShareChange(ctx, resourceID, resourceType, ownerID)
+	}
+
+	return &dto.ShareResult{Share: share, Result: string(change)}, nil
+}
+
+// UpdateSharePermission changes the permission of an existing share
+func (s *AssetService) UpdateSharePermission(ctx context.Context, resourceID, ownerID uuid.UUID, resourceType, principalType string, principalID uuid.UUID, req *dto.UpdateShareRequest) (*models.Share, error) {
+	target, err := s.getShareTarget(ctx, resourceID, ownerID, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureTeamWritable(ctx, target.TeamID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkGrantCeiling(ctx, ownerID, target, req.Permission); err != nil {
+		return nil, err
+	}
+
+	principal := models.SharePrincipal{Type: principalType, ID: principalID}
+	share, err := s.assetRepo.UpdateSharePermission(ctx, resourceID, resourceType, principal, req.Permission)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("share not found")
+		}
+		return nil, err
+	}
+
+	s.publishShareChange(ctx, resourceID, resourceType, ownerID)
+	return share, nil
+}
+
+// RevokeShare revokes sharing permissions for a resource
+func (s *AssetService) RevokeShare(ctx context.Context, resourceID, ownerID uuid.UUID, resourceType, principalType string, principalID uuid.UUID) error {
+	target, err := s.getShareTarget(ctx, resourceID, ownerID, resourceType)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ensureTeamWritable(ctx, target.TeamID); err != nil {
+		return err
+	}
+
+	// Delete share
+	principal := models.SharePrincipal{Type: principalType, ID: principalID}
+	if err := s.assetRepo.DeleteShare(ctx, resourceID, resourceType, principal); err != nil {
+		return err
+	}
+
+	s.publishShareChange(ctx, resourceID, resourceType, ownerID)
+	return nil
+}
+
+// GetResourceShares lists who a folder or note is shared with, including shares
+// inherited from parent folders, for users who may manage its shares.
+func (s *AssetService) GetResourceShares(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) ([]dto.ShareEntry, error) {
+	target, err := s.getShareTarget(ctx, resourceID, userID, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	var shares []models.Share
+	if resourceType == "note" {
+		shares, err = s.assetRepo.GetResourceShares(ctx, "note", []uuid.UUID{resourceID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Folder shares apply to every sub-folder, so walk up from the nearest folder
+	ancestors, err := s.assetRepo.GetFolderAncestors(ctx, target.FolderID)
+	if err != nil {
+		return nil, err
+	}
+	folderIDs := make([]uuid.UUID, len(ancestors))
+	for i, ancestor := range ancestors {
+		folderIDs[len(ancestors)-1-i] = ancestor.ID
+	}
+	folderShares, err := s.assetRepo.GetResourceShares(ctx, "folder", folderIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, folderID := range folderIDs {
+		for _, share := range folderShares {
+			if share.ResourceID == folderID {
+				shares = append(shares, share)
+			}
+		}
+	}
+
+	entries := make([]dto.ShareEntry, 0, len(shares))
+	var userIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool, len(shares))
+	for _, share := range shares {
+		entry := dto.ShareEntry{
+			PrincipalType: share.PrincipalType,
+			PrincipalID:   share.PrincipalID,
+			Permission:    share.Permission,
+			ExpiresAt:     share.ExpiresAt,
+			CreatedAt:     share.CreatedAt,
+			UpdatedAt:     share.UpdatedAt,
+		}
+		if share.ResourceID != resourceID {
+			inheritedFrom := share.ResourceID
+			entry.InheritedFrom = &inheritedFrom
+		}
+		entries = append(entries, entry)
+		if share.PrincipalType == models.PrincipalUser && !seen[share.PrincipalID] {
+			seen[share.PrincipalID] = true
+			userIDs = append(userIDs, share.PrincipalID)
+		}
+	}
+
+	// Fetch user details from user service, and names of team and group principals
+	var users []models.User
+	if len(userIDs) > 0 {
+		users, err = s.userService.GetUsersByIDs(ctx, userIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uuid.UUID]*models.User, len(users))
+	for i := range users {
+		byID[users[i].ID] = &users[i]
+	}
+	names := make(map[uuid.UUID]string)
+	for i := range entries {
+		entry := &entries[i]
+		switch entry.PrincipalType {
+		case models.PrincipalUser:
+			entry.User = byID[entry.PrincipalID]
+			continue
+		case models.PrincipalTeam:
+			if _, ok := names[entry.PrincipalID]; !ok {
+				if team, err := s.teamRepo.GetTeamByID(ctx, entry.PrincipalID); err == nil {
+					names[entry.PrincipalID] = team.TeamName
+				}
+			}
+		case models.PrincipalGroup:
+			if _, ok := names[entry.PrincipalID]; !ok {
+				if group, err := s.groupRepo.GetGroupByID(ctx, entry.PrincipalID); err == nil {
+					names[entry.PrincipalID] = group.Name
+				}
+			}
+		}
+		entry.Name = names[entry.PrincipalID]
+	}
+	return entries, nil
+}
+
+// TransferFolder hands a folder to another member of its team; sub-folders and notes keep their owners
+func (s *AssetService) TransferFolder(ctx context.Context, folderID, userID uuid.UUID, req *dto.TransferAssetRequest) (*models.Folder, error) {
+	folder, err := s.authorizeFolder(ctx, folderID, userID, policy.Transfer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNewOwner(ctx, folder.TeamID, folder.OwnerID, req.OwnerID); err != nil {
+		return nil, err
+	}
+
+	if err := s.assetRepo.TransferOwner(ctx, folderID, "folder", req.OwnerID); err != nil {
+		return nil, err
+	}
+	folder, err = s.assetRepo.GetFolderByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishFolderChange(ctx, redisclient.ChangeFolderUpdated, folder, userID)
+	return folder, nil
+}
+
+// TransferNote hands a note to another member of its team
+func (s *AssetService) TransferNote(ctx context.Context, noteID, userID uuid.UUID, req *dto.TransferAssetRequest) (*models.Note, error) {
+	note, folder, err := s.authorizeNote(ctx, noteID, userID, policy.Transfer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkNewOwner(ctx, folder.TeamID, note.OwnerID, req.OwnerID); err != nil {
+		return nil, err
+	}
+
+	if err := s.assetRepo.TransferOwner(ctx, noteID, "note", req.OwnerID); err != nil {
+		return nil, err
+	}
+	note, err = s.assetRepo.GetNoteByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishNoteChange(ctx, redisclient.ChangeNoteUpdated, note, userID)
+	return note, nil
+}
+
+// checkNewOwner requires the new owner to be someone else on the asset's (writable) team
+func (s *AssetService) checkNewOwner(ctx context.Context, teamID, currentOwnerID, newOwnerID uuid.UUID) error {
+	if newOwnerID == currentOwnerID {
+		return errors.New("user already owns this asset")
+	}
+	if err := s.ensureTeamWritable(ctx, teamID); err != nil {
+		return err
+	}
+	isMember, err := s.teamRepo.IsUserInTeam(ctx, teamID, newOwnerID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("new owner is not a member of this team")
+	}
+	return nil
+}
+
+// GetSharedWithMe returns a page of the folders and notes shared directly with the user, newest share first
+func (s *AssetService) GetSharedWithMe(ctx context.Context, userID uuid.UUID, req *dto.SharedWithMeRequest) (*dto.Page[dto.SharedItem], error) {
+	limit := pageLimit(req.Limit)
+	filter := repositories.SharedWithUserFilter{
+		UserID:       userID,
+		ResourceType: req.Type,
+		Limit:        limit + 1,
+	}
+	if req.Cursor != "" {
+		cursor, err := decodePageCursor(req.Cursor, "sharedAt:"+req.Type)
+		if err != nil {
+			return nil, err
+		}
+		sharedAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, errInvalidCursor
+		}
+		filter.After = &repositories.KeysetCursor{Value: sharedAt, ID: cursor.ID}
+	}
+
+	resources, err := s.assetRepo.GetSharedWithUser(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &dto.Page[dto.SharedItem]{Items: make([]dto.SharedItem, 0, len(resources))}
+	if len(resources) > limit {
+		resources = resources[:limit]
+		last := resources[limit-1]
+		page.NextCursor = encodePageCursor(pageCursor{
+			Sort:  "sharedAt:" + req.Type,
+			Value: last.SharedAt.Format(time.RFC3339Nano),
+			ID:    last.ShareID,
+		})
+	}
+	for _, resource := range resources {
+		page.Items = append(page.Items, dto.SharedItem{
+			ID:         resource.ResourceID,
+			Type:       resource.ResourceType,
+			Name:       resource.Name,
+			TeamID:     resource.TeamID,
+			OwnerID:    resource.OwnerID,
+			Via:        resource.Via,
+			Permission: resource.Permission,
+			ExpiresAt:  resource.ExpiresAt,
+			SharedAt:   resource.SharedAt,
+			UpdatedAt:  resource.UpdatedAt,
+		})
+	}
+	return page, nil
+}
+
+// searchCursor is the keyset position of the last result of a search page
+type searchCursor struct {
+	Rank float64   `json:"r"`
+	ID   uuid.UUID `json:"id"`
+}
+
+func encodeSearchCursor(c searchCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeSearchCursor(s string) (*searchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var c searchCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, errInvalidCursor
+	}
+	return &c, nil
+}
+
+// parseOptionalID parses an optional, already validated UUID filter
+func parseOptionalID(s string) (*uuid.UUID, error) {
+	if s == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// SearchNotes full-text searches the titles and content of notes the user can read.
+// Access follows policy.Allowed for reads, evaluated in SQL by the repository.
+func (s *AssetService) SearchNotes(ctx context.Context, userID uuid.UUID, req *dto.SearchNotesRequest) (*dto.NoteSearchResponse, error) {
+	filter := repositories.NoteSearchFilter{
+		Query:  req.Query,
+		UserID: userID,
+		Limit:  pageLimit(req.Limit),
+	}
+
+	var err error
+	if filter.TeamID, err = parseOptionalID(req.TeamID); err != nil {
+		return nil, err
+	}
+	if filter.FolderID, err = parseOptionalID(req.FolderID); err != nil {
+		return nil, err
+	}
+	if filter.OwnerID, err = parseOptionalID(req.OwnerID); err != nil {
+		return nil, err
+	}
+	if !req.UpdatedFrom.IsZero() {
+		filter.UpdatedFrom = &req.UpdatedFrom
+	}
+	if !req.UpdatedTo.IsZero() {
+		filter.UpdatedTo = &req.UpdatedTo
+	}
+	if filter.UpdatedFrom != nil && filter.UpdatedTo != nil && filter.UpdatedFrom.After(*filter.UpdatedTo) {
+		return nil, errors.New("updatedFrom must not be after updatedTo")
+	}
+
+	if req.Tags != "" {
+		filter.Tags = parseTagFilter(req.Tags, req.TagMatch)
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeSearchCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.AfterRank = &cursor.Rank
+		filter.AfterID = &cursor.ID
+	}
+
+	// Fetch one extra row to know whether another page exists
+	limit := filter.Limit
+	filter.Limit++
+	results, err := s.assetRepo.SearchNotes(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.NoteSearchResponse{Results: []dto.NoteSearchHit{}}
+	if len(results) > limit {
+		results = results[:limit]
+		last := results[limit-1]
+		response.NextCursor = encodeSearchCursor(searchCursor{Rank: last.Rank, ID: last.ID})
+	}
+	for _, r := range results {
+		response.Results = append(response.Results, dto.NoteSearchHit{
+			ID:             r.ID,
+			Title:          r.Title,
+			TitleHighlight: r.TitleHighlight,
+			Snippet:        r.Snippet,
+			FolderID:       r.FolderID,
+			OwnerID:        r.OwnerID,
+			TeamID:         r.TeamID,
+			UpdatedAt:      r.UpdatedAt,
+			Rank:           r.Rank,
+		})
+	}
+	return response, nil
+}
+
+// getTeamTag loads a tag and checks that it belongs to the asset's team
+func (s *AssetService) getTeamTag(ctx context.Context, tagID string, teamID uuid.UUID) (*models.Tag, error) {
+	id, err := uuid.Parse(tagID)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.tagRepo.GetTagByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	if tag.TeamID != teamID {
+		return nil, errors.New("tag belongs to another team")
+	}
+	return tag, nil
+}
+
+// GetNoteTags lists the tags of a note the user can read
+func (s *AssetService) GetNoteTags(ctx context.Context, noteID, userID uuid.UUID) ([]models.Tag, error) {
+	if _, err := s.GetNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+	return s.tagRepo.GetNoteTags(ctx, noteID)
+}
+
+// AttachNoteTag tags a note the user can edit with a tag of the note's team
+func (s *AssetService) AttachNoteTag(ctx context.Context, noteID, userID uuid.UUID, req *dto.AttachTagRequest) ([]models.Tag, error) {
+	note, err := s.getWritableNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.getTeamTag(ctx, req.TagID, note.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.AttachNoteTag(ctx, noteID, tag.ID); err != nil {
+		return nil, err
+	}
+	return s.tagRepo.GetNoteTags(ctx, noteID)
+}
+
+// DetachNoteTag removes a tag from a note the user can edit
+func (s *AssetService) DetachNoteTag(ctx context.Context, noteID, tagID, userID uuid.UUID) error {
+	if _, err := s.getWritableNote(ctx, noteID, userID); err != nil {
+		return err
+	}
+	return s.tagRepo.DetachNoteTag(ctx, noteID, tagID)
+}
+
+// GetFolderTags lists the tags of a folder the user can read
+func (s *AssetService) GetFolderTags(ctx context.Context, folderID, userID uuid.UUID) ([]models.Tag, error) {
+	if _, err := s.authorizeFolder(ctx, folderID, userID, policy.Read); err != nil {
+		return nil, err
+	}
+	return s.tagRepo.GetFolderTags(ctx, folderID)
+}
+
+// AttachFolderTag tags a folder the user can edit with a tag of the folder's team
+func (s *AssetService) AttachFolderTag(ctx context.Context, folderID, userID uuid.UUID, req *dto.AttachTagRequest) ([]models.Tag, error) {
+	folder, err := s.authorizeFolder(ctx, folderID, userID, policy.Write)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return nil, err
+	}
+	tag, err := s.getTeamTag(ctx, req.TagID, folder.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tagRepo.AttachFolderTag(ctx, folderID, tag.ID); err != nil {
+		return nil, err
+	}
+	return s.tagRepo.GetFolderTags(ctx, folderID)
+}
+
+// DetachFolderTag removes a tag from a folder the user can edit
+func (s *AssetService) DetachFolderTag(ctx context.Context, folderID, tagID, userID uuid.UUID) error {
+	folder, err := s.authorizeFolder(ctx, folderID, userID, policy.Write)
+	if err != nil {
+		return err
+	}
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return err
+	}
+	return s.tagRepo.DetachFolderTag(ctx, folderID, tagID)
+}
+
+// GetTaggedAssets lists the folders and notes the user can read that match the tag filter
+func (s *AssetService) GetTaggedAssets(ctx context.Context, userID uuid.UUID, req *dto.TaggedAssetsRequest) (*dto.TaggedAssetsResponse, error) {
+	filter := parseTagFilter(req.Tags, req.Match)
+	if len(filter.Names) == 0 {
+		return nil, errors.New("at least one tag is required")
+	}
+	teamID, err := parseOptionalID(req.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	folders, err := s.assetRepo.GetTaggedFolders(ctx, userID, teamID, filter)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.assetRepo.GetTaggedNotes(ctx, userID, teamID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TaggedAssetsResponse{Folders: folders, Notes: notes}, nil
+}
+
+// GetMyTrash lists trashed items the user owns or deleted
+func (s *AssetService) GetMyTrash(ctx context.Context, userID uuid.UUID) (*dto.TrashResponse, error) {
+	folders, notes, err := s.assetRepo.GetUserTrash(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TrashResponse{Folders: folders, Notes: notes}, nil
+}
+
+// GetTeamTrash lists every trashed item of a team (managers only)
+func (s *AssetService) GetTeamTrash(ctx context.Context, teamID, userID uuid.UUID) (*dto.TrashResponse, error) {
+	allowed, err := s.policy.Can(ctx, userID, policy.Manage, policy.TeamResource(teamID))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, errors.New("only team managers can view the team trash")
+	}
+
+	folders, notes, err := s.assetRepo.GetTeamTrash(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TrashResponse{Folders: folders, Notes: notes}, nil
+}
+
+// RestoreFolder brings a trashed folder back with everything deleted along with it.
+// If its parent is still in the trash the folder is restored at the top level.
+func (s *AssetService) RestoreFolder(ctx context.Context, folderID, userID uuid.UUID) (*models.Folder, error) {
+	folder, err := s.assetRepo.GetTrashedFolder(ctx, folderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("folder not found in trash")
+		}
+		return nil, err
+	}
+	if folder.DeletedWith != nil {
+		return nil, errors.New("folder was deleted with its parent, restore the parent folder instead")
+	}
+
+	// Same rule as deletion: owner or team manager
+	if err := s.policy.Authorize(ctx, userID, policy.Delete, policy.FolderResource(folder)); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return nil, err
+	}
+
+	detach := false
+	if folder.ParentID != nil {
+		if _, err := s.assetRepo.GetFolderByID(ctx, *folder.ParentID); err != nil {
+			detach = true
+		}
+	}
+
+	if err := s.assetRepo.RestoreFolder(ctx, folderID, detach); err != nil {
+		return nil, err
+	}
+	folder, err = s.assetRepo.GetFolderByID(ctx, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishFolderChange(ctx, redisclient.ChangeFolderRestored, folder, userID)
+	return folder, nil
+}
+
+// RestoreNote brings a trashed note back into its folder
+func (s *AssetService) RestoreNote(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, error) {
+	note, err := s.assetRepo.GetTrashedNote(ctx, noteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("note not found in trash")
+		}
+		return nil, err
+	}
+	if note.DeletedWith != nil {
+		return nil, errors.New("note was deleted with its folder, restore the folder instead")
+	}
+
+	folder, err := s.assetRepo.GetFolderByID(ctx, note.FolderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("the note's folder is in the trash, restore it first")
+		}
+		return nil, err
+	}
+
+	// Same rule as deletion: note owner, folder owner or team manager
+	if err := s.policy.Authorize(ctx, userID, policy.Delete, policy.NoteResource(note, folder)); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return nil, err
+	}
+
+	if err := s.assetRepo.RestoreNote(ctx, noteID); err != nil {
+		return nil, err
+	}
+	note, err = s.assetRepo.GetNoteByID(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishNoteChange(ctx, redisclient.ChangeNoteRestored, note, userID)
+	return note, nil
+}
+
+// RunTrashPurge periodically deletes items that have been in the trash longer than retention
+func (s *AssetService) RunTrashPurge(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.assetRepo.PurgeTrash(ctx, time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("Failed to purge trash: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Purged %d trashed items", purged)
+			}
+		}
+	}
+}
+
+// ExpireShares deletes the shares past their expiry and emits a SHARE_EXPIRED event for each
+func (s *AssetService) ExpireShares(ctx context.Context) (int, error) {
+	expired, err := s.assetRepo.DeleteExpiredShares(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	for _, share := range expired {
+		s.publishShareChange(ctx, share.ResourceID, share.ResourceType, uuid.Nil)
+	}
+	if s.producer != nil {
+		for _, share := range expired {
+			err := s.producer.SendShareEvent(kafka.ShareEvent{
+				EventType:     kafka.EventShareExpired,
+				TeamID:        share.TeamID,
+				ResourceID:    share.ResourceID,
+				ResourceType:  share.ResourceType,
+				OwnerID:       share.OwnerID,
+				PrincipalType: share.PrincipalType,
+				PrincipalID:   share.PrincipalID,
+				Permission:    share.Permission,
+			})
+			if err != nil {
+				log.Printf("Failed to send Kafka event for share expiry: %v", err)
+			}
+		}
+	}
+	return len(expired), nil
+}
+
+// RunShareExpiry removes expired shares every interval until ctx is cancelled.
+// Access checks already ignore expired shares, so the sweep only cleans up and notifies.
+func (s *AssetService) RunShareExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.ExpireShares(ctx)
+			if err != nil {
+				log.Printf("Failed to remove expired shares: %v", err)
+				continue
+			}
+			if removed > 0 {
+				log.Printf("Removed %d expired shares", removed)
+			}
+		}
+	}
+}
+
+// GetTeamAssets lists a page of a team's folders if the user is a manager
+func (s *AssetService) GetTeamAssets(ctx context.Context, teamID, userID uuid.UUID, req *dto.AssetListRequest) (*dto.Page[models.Folder], error) {
+	allowed, err := s.policy.Can(ctx, userID, policy.Manage, policy.TeamResource(teamID))
+	if err != nil {
+		return nil, err
 	}
-	if !isManager && targetUserID != currentUserID {
-		return nil, errors.New("only team managers can view other users' assets")
+	if !allowed {
+		return nil, errors.New("only team managers can view all team assets")
+	}
+
+	opts, err := folderListOptions(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retrieve team assets
+	folders, err := s.assetRepo.GetTeamAssets(ctx, teamID, opts)
+	if err != nil {
+		return nil, err
+	}
+	folders, next := folderPage(folders, req)
+	return &dto.Page[models.Folder]{Items: folders, NextCursor: next}, nil
+}
+
+// GetUserAssets lists a page of a user's folders, grouped per team in page order.
+// Managers only see the teams they manage; users always see all of their own teams.
+func (s *AssetService) GetUserAssets(ctx context.Context, targetUserID, currentUserID uuid.UUID, req *dto.AssetListRequest) (*dto.Page[dto.TeamAssets], error) {
+	targetTeamIDs, err := s.teamRepo.GetUserTeamIDs(ctx, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	visibleTeamIDs := targetTeamIDs
+	if targetUserID != currentUserID {
+		visibleTeamIDs = make([]uuid.UUID, 0, len(targetTeamIDs))
+		for _, teamID := range targetTeamIDs {
+			allowed, err := s.policy.Can(ctx, currentUserID, policy.Manage, policy.TeamResource(teamID))
+			if err != nil {
+				return nil, err
+			}
+			if allowed {
+				visibleTeamIDs = append(visibleTeamIDs, teamID)
+			}
+		}
+		if len(visibleTeamIDs) == 0 {
+			return nil, errors.New("only team managers can view other users' assets")
+		}
+	}
+
+	opts, err := folderListOptions(req)
+	if err != nil {
+		return nil, err
 	}
 
 	// Retrieve user assets
-	return s.assetRepo.GetUserAssets(ctx, targetUserID)
+	folders, err := s.assetRepo.GetUserAssets(ctx, targetUserID, visibleTeamIDs, opts)
+	if err != nil {
+		return nil, err
+	}
+	folders, next := folderPage(folders, req)
+
+	groups := make([]dto.TeamAssets, 0)
+	index := make(map[uuid.UUID]int)
+	for _, folder := range folders {
+		i, ok := index[folder.TeamID]
+		if !ok {
+			i = len(groups)
+			index[folder.TeamID] = i
+			groups = append(groups, dto.TeamAssets{TeamID: folder.TeamID, Folders: []models.Folder{}})
+		}
+		groups[i].Folders = append(groups[i].Folders, folder)
+	}
+
+	return &dto.Page[dto.TeamAssets]{Items: groups, NextCursor: next}, nil
 }
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go_service/internal/models"
+	"go_service/internal/repositories"
+	"go_service/pkg/blobstore"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type IAttachmentService interface {
+	UploadAttachment(ctx context.Context, noteID, userID uuid.UUID, file *multipart.FileHeader) (*models.Attachment, error)
+	GetAttachments(ctx context.Context, noteID, userID uuid.UUID) ([]models.Attachment, error)
+	OpenAttachment(ctx context.Context, noteID, attachmentID, userID uuid.UUID) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, noteID, attachmentID, userID uuid.UUID) error
+	RunOrphanSweep(ctx context.Context, interval time.Duration)
+}
+
+// AttachmentLimits caps single uploads and the total bytes stored per team
+type AttachmentLimits struct {
+	MaxFileSize int64
+	TeamQuota   int64
+}
+
+type AttachmentService struct {
+	repo   repositories.IAttachmentRepository
+	assets *AssetService
+	store  blobstore.Store
+	limits AttachmentLimits
+}
+
+// NewAttachmentService reuses the asset service so attachments follow the note's permissions
+func NewAttachmentService(repo repositories.IAttachmentRepository, assets *AssetService, store blobstore.Store, limits AttachmentLimits) *AttachmentService {
+	return &AttachmentService{
+		repo:   repo,
+		assets: assets,
+		store:  store,
+		limits: limits,
+	}
+}
+
+// UploadAttachment stores a file on a note the user can edit
+func (s *AttachmentService) UploadAttachment(ctx context.Context, noteID, userID uuid.UUID, file *multipart.FileHeader) (*models.Attachment, error) {
+	note, err := s.assets.getWritableNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	folder, err := s.assets.assetRepo.GetFolderByID(ctx, note.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Size > s.limits.MaxFileSize {
+		return nil, fmt.Errorf("file exceeds the maximum size of %d bytes", s.limits.MaxFileSize)
+	}
+	// Cheap pre-check; the quota is enforced again when the metadata is written
+	used, err := s.repo.GetTeamUsage(ctx, folder.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if used+file.Size > s.limits.TeamQuota {
+		return nil, repositories.ErrQuotaExceeded
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	// Sniff the MIME type from the content rather than trusting the client
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	mimeType := http.DetectContentType(head[:n])
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		ID:         uuid.New(),
+		NoteID:     note.ID,
+		TeamID:     folder.TeamID,
+		FileName:   filepath.Base(file.Filename),
+		Size:       file.Size,
+		MimeType:   mimeType,
+		UploadedBy: userID,
+	}
+	attachment.StorageKey = fmt.Sprintf("teams/%s/notes/%s/%s", attachment.TeamID, attachment.NoteID, attachment.ID)
+
+	hash := sha256.New()
+	if err := s.store.Put(ctx, attachment.StorageKey, io.TeeReader(src, hash), file.Size, mimeType); err != nil {
+		return nil, err
+	}
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if err := s.repo.CreateAttachment(ctx, attachment, s.limits.TeamQuota); err != nil {
+		if delErr := s.store.Delete(ctx, attachment.StorageKey); delErr != nil {
+			log.Printf("Failed to remove blob %s after rejected upload: %v", attachment.StorageKey, delErr)
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// GetAttachments lists the attachments of a note the user can read
+func (s *AttachmentService) GetAttachments(ctx context.Context, noteID, userID uuid.UUID) ([]models.Attachment, error) {
+	if _, err := s.assets.GetNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetNoteAttachments(ctx, noteID)
+}
+
+// OpenAttachment returns the metadata and content of an attachment; the caller closes the reader
+func (s *AttachmentService) OpenAttachment(ctx context.Context, noteID, attachmentID, userID uuid.UUID) (*models.Attachment, io.ReadCloser, error) {
+	if _, err := s.assets.GetNote(ctx, noteID, userID); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, noteID, attachmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("attachment not found")
+		}
+		return nil, nil, err
+	}
+
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return nil, nil, errors.New("attachment content is missing")
+		}
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment removes an attachment from a note the user can edit
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, noteID, attachmentID, userID uuid.UUID) error {
+	if _, err := s.assets.getWritableNote(ctx, noteID, userID); err != nil {
+		return err
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, noteID, attachmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("attachment not found")
+		}
+		return err
+	}
+
+	if err := s.repo.DeleteAttachment(ctx, attachment.ID); err != nil {
+		return err
+	}
+	// The metadata is gone, so a failed blob delete only leaves unreachable bytes behind
+	if err := s.store.Delete(ctx, attachment.StorageKey); err != nil {
+		log.Printf("Failed to delete blob %s: %v", attachment.StorageKey, err)
+	}
+	return nil
+}
+
+// sweepBatch is how many queued blob deletions the sweep loads at a time
+const sweepBatch = 500
+
+// sweepOrphans queues the attachments whose note no longer exists, then deletes the queued blobs.
+// A blob that cannot be deleted stays queued for the next run without holding up the ones after it.
+func (s *AttachmentService) sweepOrphans(ctx context.Context) (int, error) {
+	if _, err := s.repo.QueueOrphanedAttachments(ctx); err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	after := uuid.Nil
+	for {
+		deletions, err := s.repo.GetBlobDeletions(ctx, after, sweepBatch)
+		if err != nil {
+			return removed, err
+		}
+		for _, deletion := range deletions {
+			after = deletion.ID
+			if err := s.store.Delete(ctx, deletion.StorageKey); err != nil {
+				log.Printf("Failed to delete orphaned blob %s: %v", deletion.StorageKey, err)
+				continue
+			}
+			if err := s.repo.DeleteBlobDeletion(ctx, deletion.ID); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+		if len(deletions) < sweepBatch {
+			return removed, nil
+		}
+	}
+}
+
+// RunOrphanSweep deletes the blobs of purged notes and deleted teams until ctx is cancelled
+func (s *AttachmentService) RunOrphanSweep(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.sweepOrphans(ctx)
+			if err != nil {
+				log.Printf("Failed to sweep orphaned attachments: %v", err)
+			} else if removed > 0 {
+				log.Printf("Removed %d orphaned attachment blobs", removed)
+			}
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go_service/internal/dto"
+	"go_service/internal/models"
+	"go_service/internal/policy"
+	"go_service/internal/repositories"
+	"go_service/pkg/kafka"
+	"go_service/pkg/redisclient"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// mentionPattern matches an @ followed by a user ID or an email address, e.g. "@jane@example.com".
+// The @ must not follow a word character so that plain email addresses are not taken as mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[\w.%+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+// maxCommentMentions caps the users resolved per comment; later mentions stay plain text
+const maxCommentMentions = 20
+
+type ICommentService interface {
+	GetComments(ctx context.Context, noteID, userID uuid.UUID, req *dto.CommentListRequest) ([]dto.CommentThread, error)
+	CreateComment(ctx context.Context, noteID, userID uuid.UUID, req *dto.CreateCommentRequest) (*models.NoteComment, error)
+	UpdateComment(ctx context.Context, noteID, commentID, userID uuid.UUID, req *dto.UpdateCommentRequest) (*models.NoteComment, error)
+	DeleteComment(ctx context.Context, noteID, commentID, userID uuid.UUID) error
+	SetResolved(ctx context.Context, noteID, commentID, userID uuid.UUID, resolved bool) (*models.NoteComment, error)
+}
+
+type CommentService struct {
+	repo     repositories.ICommentRepository
+	assets   *AssetService
+	users    IUserService
+	producer *kafka.Producer
+}
+
+// NewCommentService reuses the asset service so comments follow the note's permissions
+func NewCommentService(repo repositories.ICommentRepository, assets *AssetService, producer *kafka.Producer) *CommentService {
+	return &CommentService{
+		repo:     repo,
+		assets:   assets,
+		users:    NewUserService(),
+		producer: producer,
+	}
+}
+
+// GetComments lists the threads on a note the user can read, oldest first
+func (s *CommentService) GetComments(ctx context.Context, noteID, userID uuid.UUID, req *dto.CommentListRequest) ([]dto.CommentThread, error) {
+	if _, err := s.assets.GetNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+	comments, err := s.repo.GetNoteComments(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	replies := make(map[uuid.UUID][]models.NoteComment)
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			replies[*comment.ParentID] = append(replies[*comment.ParentID], comment)
+		}
+	}
+	threads := []dto.CommentThread{}
+	for _, comment := range comments {
+		if comment.ParentID != nil {
+			continue
+		}
+		if (req.Status == "open" && comment.ResolvedAt != nil) || (req.Status == "resolved" && comment.ResolvedAt == nil) {
+			continue
+		}
+		thread := dto.CommentThread{NoteComment: comment, Replies: replies[comment.ID]}
+		if thread.Replies == nil {
+			thread.Replies = []models.NoteComment{}
+		}
+		threads = append(threads, thread)
+	}
+	return threads, nil
+}
+
+// CreateComment posts a comment or a reply; replies to a reply join the thread of its top-level comment
+func (s *CommentService) CreateComment(ctx context.Context, noteID, userID uuid.UUID, req *dto.CreateCommentRequest) (*models.NoteComment, error) {
+	note, folder, err := s.commentableNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.NoteComment{
+		NoteID:   note.ID,
+		AuthorID: userID,
+		Body:     req.Body,
+	}
+	if req.ParentID != nil {
+		parent, err := s.getComment(ctx, noteID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		comment.ParentID = &parent.ID
+		if parent.ParentID != nil {
+			comment.ParentID = parent.ParentID
+		}
+	}
+	comment.Mentions = s.resolveMentions(ctx, req.Body, policy.NoteResource(note, folder), userID)
+
+	if err := s.repo.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	s.assets.publishChange(ctx, redisclient.Change{
+		Type:         redisclient.ChangeCommentCreated,
+		ResourceType: "note",
+		ResourceID:   note.ID,
+		FolderID:     &note.FolderID,
+		ActorID:      userID,
+		CommentID:    &comment.ID,
+	}, redisclient.NoteChannel(note.ID), redisclient.FolderChannel(note.FolderID))
+	s.publish(kafka.EventCommentCreated, folder.TeamID, comment, uuid.Nil)
+	for _, mention := range comment.Mentions {
+		s.publish(kafka.EventCommentMention, folder.TeamID, comment, mention.UserID)
+	}
+	return comment, nil
+}
+
+// UpdateComment replaces the body of the user's own comment; only newly mentioned users are notified
+func (s *CommentService) UpdateComment(ctx context.Context, noteID, commentID, userID uuid.UUID, req *dto.UpdateCommentRequest) (*models.NoteComment, error) {
+	note, folder, err := s.commentableNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := s.getComment(ctx, noteID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, errors.New("only the author can edit a comment")
+	}
+	if comment.DeletedAt != nil {
+		return nil, errors.New("comment was deleted")
+	}
+
+	previous := make(map[uuid.UUID]bool, len(comment.Mentions))
+	for _, mention := range comment.Mentions {
+		previous[mention.UserID] = true
+	}
+
+	now := time.Now()
+	comment.Body = req.Body
+	comment.EditedAt = &now
+	comment.Mentions = s.resolveMentions(ctx, req.Body, policy.NoteResource(note, folder), userID)
+	if err := s.repo.UpdateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	for _, mention := range comment.Mentions {
+		if !previous[mention.UserID] {
+			s.publish(kafka.EventCommentMention, folder.TeamID, comment, mention.UserID)
+		}
+	}
+	return comment, nil
+}
+
+// DeleteComment removes the user's own comment
+func (s *CommentService) DeleteComment(ctx context.Context, noteID, commentID, userID uuid.UUID) error {
+	if _, _, err := s.commentableNote(ctx, noteID, userID); err != nil {
+		return err
+	}
+	comment, err := s.getComment(ctx, noteID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID {
+		return errors.New("only the author can delete a comment")
+	}
+	if comment.DeletedAt != nil {
+		return errors.New("comment was deleted")
+	}
+	return s.repo.DeleteComment(ctx, comment)
+}
+
+// SetResolved resolves or reopens a thread; anyone who may comment on the note can do either
+func (s *CommentService) SetResolved(ctx context.Context, noteID, commentID, userID uuid.UUID, resolved bool) (*models.NoteComment, error) {
+	if _, _, err := s.commentableNote(ctx, noteID, userID); err != nil {
+		return nil, err
+	}
+	comment, err := s.getComment(ctx, noteID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.ParentID != nil {
+		return nil, errors.New("only top-level comments can be resolved")
+	}
+	if (comment.ResolvedAt != nil) == resolved {
+		return comment, nil
+	}
+
+	var resolvedBy *uuid.UUID
+	if resolved {
+		resolvedBy = &userID
+	}
+	if err := s.repo.SetResolved(ctx, comment.ID, resolvedBy); err != nil {
+		return nil, err
+	}
+	return s.repo.GetComment(ctx, noteID, commentID)
+}
+
+// commentableNote loads a note the user may comment on; comments count as edits for archived teams
+func (s *CommentService) commentableNote(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, *models.Folder, error) {
+	note, folder, err := s.assets.authorizeNote(ctx, noteID, userID, policy.Comment)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.assets.ensureTeamWritable(ctx, folder.TeamID); err != nil {
+		return nil, nil, err
+	}
+	return note, folder, nil
+}
+
+func (s *CommentService) getComment(ctx context.Context, noteID, commentID uuid.UUID) (*models.NoteComment, error) {
+	comment, err := s.repo.GetComment(ctx, noteID, commentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("comment not found")
+		}
+		return nil, err
+	}
+	return comment, nil
+}
+
+// resolveMentions looks up the users @mentioned in body through the user service. Unknown users,
+// the author and users who cannot read the note are dropped, so a mention never leaks the note.
+func (s *CommentService) resolveMentions(ctx context.Context, body string, note policy.Resource, authorID uuid.UUID) []models.CommentMention {
+	var userIDs []uuid.UUID
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		token := match[1]
+		key := strings.ToLower(token)
+		if seen[key] || len(seen) == maxCommentMentions {
+			continue
+		}
+		seen[key] = true
+
+		if id, err := uuid.Parse(token); err == nil {
+			userIDs = append(userIDs, id)
+			continue
+		}
+		user, err := s.users.GetUserByEmail(ctx, token)
+		if err != nil {
+			continue
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	if len(userIDs) == 0 {
+		return []models.CommentMention{}
+	}
+
+	// Mentions by ID must still name a real user
+	users, err := s.users.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		log.Printf("Failed to resolve comment mentions: %v", err)
+		return []models.CommentMention{}
+	}
+
+	mentions := []models.CommentMention{}
+	added := make(map[uuid.UUID]bool)
+	for _, user := range users {
+		if user.ID == authorID || added[user.ID] {
+			continue
+		}
+		canRead, err := s.assets.policy.Can(ctx, user.ID, policy.Read, note)
+		if err != nil || !canRead {
+			continue
+		}
+		added[user.ID] = true
+		mentions = append(mentions, models.CommentMention{UserID: user.ID})
+	}
+	return mentions
+}
+
+// publish sends a comment event, to mentionedUserID for COMMENT_MENTION
+func (s *CommentService) publish(eventType string, teamID uuid.UUID, comment *models.NoteComment, mentionedUserID uuid.UUID) {
+	if s.producer == nil {
+		return
+	}
+	err := s.producer.SendCommentEvent(kafka.CommentEvent{
+		EventType:       eventType,
+		TeamID:          teamID,
+		NoteID:          comment.NoteID,
+		CommentID:       comment.ID,
+		ParentID:        comment.ParentID,
+		AuthorID:        comment.AuthorID,
+		MentionedUserID: mentionedUserID,
+	})
+	if err != nil {
+		log.Printf("Failed to send Kafka event for comment %s: %v", comment.ID, err)
+	}
+}
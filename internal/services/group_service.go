@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go_service/internal/dto"
+	"go_service/internal/models"
+	"go_service/internal/repositories"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type IGroupService interface {
+	CreateGroup(ctx context.Context, teamID, userID uuid.UUID, req *dto.CreateGroupRequest) (*models.Group, error)
+	GetTeamGroups(ctx context.Context, teamID, userID uuid.UUID) ([]models.Group, error)
+	UpdateGroup(ctx context.Context, teamID, groupID, userID uuid.UUID, req *dto.UpdateGroupRequest) (*models.Group, error)
+	DeleteGroup(ctx context.Context, teamID, groupID, userID uuid.UUID) error
+	AddGroupMembers(ctx context.Context, teamID, groupID, userID uuid.UUID, req *dto.GroupMembersRequest) (*models.Group, error)
+	RemoveGroupMember(ctx context.Context, teamID, groupID, memberID, userID uuid.UUID) error
+}
+
+type GroupService struct {
+	groupRepo repositories.IGroupRepository
+	teamRepo  repositories.ITeamRepository
+}
+
+func NewGroupService(groupRepo repositories.IGroupRepository, teamRepo repositories.ITeamRepository) *GroupService {
+	return &GroupService{
+		groupRepo: groupRepo,
+		teamRepo:  teamRepo,
+	}
+}
+
+// requireManager rejects users who are not a MANAGER or MAIN_MANAGER of the team
+func (s *GroupService) requireManager(ctx context.Context, teamID, userID uuid.UUID) error {
+	role, err := s.teamRepo.GetUserRoleInTeam(ctx, teamID, userID)
+	if err != nil || (role != "MANAGER" && role != "MAIN_MANAGER") {
+		return errors.New("only team managers can manage groups")
+	}
+	return nil
+}
+
+// teamMemberIDs parses user IDs and checks that each of them is on the team
+func (s *GroupService) teamMemberIDs(ctx context.Context, teamID uuid.UUID, rawIDs []string) ([]uuid.UUID, error) {
+	userIDs := make([]uuid.UUID, 0, len(rawIDs))
+	for _, raw := range rawIDs {
+		userID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		isMember, err := s.teamRepo.IsUserInTeam(ctx, teamID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, errors.New("group members must belong to the team: " + raw)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, nil
+}
+
+// getTeamGroup loads a group and makes sure it belongs to the team
+func (s *GroupService) getTeamGroup(ctx context.Context, teamID, groupID uuid.UUID) (*models.Group, error) {
+	group, err := s.groupRepo.GetGroupByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("group not found")
+		}
+		return nil, err
+	}
+	if group.TeamID != teamID {
+		return nil, errors.New("group not found")
+	}
+	return group, nil
+}
+
+// CreateGroup adds a named group of team members (managers only)
+func (s *GroupService) CreateGroup(ctx context.Context, teamID, userID uuid.UUID, req *dto.CreateGroupRequest) (*models.Group, error) {
+	if err := s.requireManager(ctx, teamID, userID); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("group name is required")
+	}
+	if _, err := s.groupRepo.GetGroupByName(ctx, teamID, name); err == nil {
+		return nil, errors.New("group already exists in this team")
+	}
+
+	memberIDs, err := s.teamMemberIDs(ctx, teamID, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	group := &models.Group{
+		TeamID:    teamID,
+		Name:      name,
+		CreatedBy: userID,
+	}
+	seen := make(map[uuid.UUID]bool, len(memberIDs))
+	for _, memberID := range memberIDs {
+		if !seen[memberID] {
+			seen[memberID] = true
+			group.Members = append(group.Members, models.GroupMember{UserID: memberID})
+		}
+	}
+	if err := s.groupRepo.CreateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// GetTeamGroups lists the groups of a team with their members for team members
+func (s *GroupService) GetTeamGroups(ctx context.Context, teamID, userID uuid.UUID) ([]models.Group, error) {
+	isMember, err := s.teamRepo.IsUserInTeam(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("user is not a member of this team")
+	}
+	return s.groupRepo.GetTeamGroups(ctx, teamID)
+}
+
+// UpdateGroup renames a group (managers only)
+func (s *GroupService) UpdateGroup(ctx context.Context, teamID, groupID, userID uuid.UUID, req *dto.UpdateGroupRequest) (*models.Group, error) {
+	if err := s.requireManager(ctx, teamID, userID); err != nil {
+		return nil, err
+	}
+	group, err := s.getTeamGroup(ctx, teamID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("group name is required")
+	}
+	if name != group.Name {
+		if _, err := s.groupRepo.GetGroupByName(ctx, teamID, name); err == nil {
+			return nil, errors.New("group already exists in this team")
+		}
+		group.Name = name
+		group.UpdatedAt = time.Now()
+		if err := s.groupRepo.UpdateGroup(ctx, group); err != nil {
+			return nil, err
+		}
+	}
+	return group, nil
+}
+
+// DeleteGroup deletes a group and revokes every share granted to it (managers only)
+func (s *GroupService) DeleteGroup(ctx context.Context, teamID, groupID, userID uuid.UUID) error {
+	if err := s.requireManager(ctx, teamID, userID); err != nil {
+		return err
+	}
+	if _, err := s.getTeamGroup(ctx, teamID, groupID); err != nil {
+		return err
+	}
+	return s.groupRepo.DeleteGroup(ctx, groupID)
+}
+
+// AddGroupMembers adds team members to a group (managers only); they gain the group's shares immediately
+func (s *GroupService) AddGroupMembers(ctx context.Context, teamID, groupID, userID uuid.UUID, req *dto.GroupMembersRequest) (*models.Group, error) {
+	if err := s.requireManager(ctx, teamID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getTeamGroup(ctx, teamID, groupID); err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := s.teamMemberIDs(ctx, teamID, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.groupRepo.AddGroupMembers(ctx, groupID, memberIDs); err != nil {
+		return nil, err
+	}
+	return s.groupRepo.GetGroupByID(ctx, groupID)
+}
+
+// RemoveGroupMember removes a user from a group (managers only)
+func (s *GroupService) RemoveGroupMember(ctx context.Context, teamID, groupID, memberID, userID uuid.UUID) error {
+	if err := s.requireManager(ctx, teamID, userID); err != nil {
+		return err
+	}
+	if _, err := s.getTeamGroup(ctx, teamID, groupID); err != nil {
+		return err
+	}
+	if err := s.groupRepo.RemoveGroupMember(ctx, groupID, memberID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user is not a member of this group")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go_service/internal/models"
+	"go_service/internal/repositories"
+	"go_service/pkg/kafka"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Invitations stay valid for a week unless accepted or declined
+const invitationTTL = 7 * 24 * time.Hour
+
+type IInvitationService interface {
+	InviteMember(ctx context.Context, teamID uuid.UUID, inviteeID uuid.UUID, email string, currentUserID uuid.UUID) (*models.Invitation, error)
+	GetTeamInvitations(ctx context.Context, teamID uuid.UUID, currentUserID uuid.UUID) ([]models.Invitation, error)
+	GetMyInvitations(ctx context.Context, userID uuid.UUID) ([]models.Invitation, error)
+	AcceptInvitation(ctx context.Context, token string, userID uuid.UUID) (*models.Invitation, error)
+	DeclineInvitation(ctx context.Context, token string, userID uuid.UUID) error
+	CleanupExpired(ctx context.Context) (int64, error)
+	RunExpiryCleanup(ctx context.Context, interval time.Duration)
+}
+
+type InvitationService struct {
+	repo        repositories.IInvitationRepository
+	teamRepo    repositories.ITeamRepository
+	userService *UserService
+	producer    *kafka.Producer
+}
+
+func NewInvitationService(repo repositories.IInvitationRepository, teamRepo repositories.ITeamRepository, producer *kafka.Producer) *InvitationService {
+	return &InvitationService{
+		repo:        repo,
+		teamRepo:    teamRepo,
+		userService: NewUserService(),
+		producer:    producer,
+	}
+}
+
+// InviteMember creates a pending invitation for a user given by ID or email
+func (s *InvitationService) InviteMember(ctx context.Context, teamID uuid.UUID, inviteeID uuid.UUID, email string, currentUserID uuid.UUID) (*models.Invitation, error) {
+	currentUserRole, err := s.teamRepo.GetUserRoleInTeam(ctx, teamID, currentUserID)
+	if err != nil || (currentUserRole != "MANAGER" && currentUserRole != "MAIN_MANAGER") {
+		return nil, errors.New("you are not a manager")
+	}
+
+	// Resolve the invitee through the user service so we never invite unknown accounts
+	if inviteeID == uuid.Nil {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			return nil, errors.New("either userId or email is required")
+		}
+		user, err := s.userService.GetUserByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+		inviteeID = user.ID
+	} else {
+		exists, err := s.userService.UserExists(ctx, inviteeID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.New("user does not exist")
+		}
+	}
+
+	isMember, err := s.teamRepo.IsUserInTeam(ctx, teamID, inviteeID)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, repositories.ErrAlreadyMember
+	}
+
+	// Cheap pre-check; the unique index on pending invitations settles concurrent invites
+	existing, err := s.repo.GetPendingInvitation(ctx, teamID, inviteeID)
+	if err == nil && existing != nil {
+		return nil, repositories.ErrAlreadyInvited
+	}
+
+	token, err := generateToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &models.Invitation{
+		TeamID:    teamID,
+		InviteeID: inviteeID,
+		InvitedBy: currentUserID,
+		Token:     token,
+		Status:    models.InvitationPending,
+		ExpiresAt: time.Now().Add(invitationTTL),
+	}
+	if err := s.repo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	if s.producer != nil {
+		err := s.producer.SendTeamEvent(
+			kafka.EventMemberInvited,
+			teamID,
+			currentUserID,
+			inviteeID,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for member invitation: %v", err)
+		}
+	}
+
+	return invitation, nil
+}
+
+// GetTeamInvitations lists pending invitations of a team (managers only)
+func (s *InvitationService) GetTeamInvitations(ctx context.Context, teamID uuid.UUID, currentUserID uuid.UUID) ([]models.Invitation, error) {
+	currentUserRole, err := s.teamRepo.GetUserRoleInTeam(ctx, teamID, currentUserID)
+	if err != nil || (currentUserRole != "MANAGER" && currentUserRole != "MAIN_MANAGER") {
+		return nil, errors.New("you are not a manager")
+	}
+
+	return s.repo.GetPendingInvitationsForTeam(ctx, teamID)
+}
+
+// GetMyInvitations lists the pending invitations addressed to the user
+func (s *InvitationService) GetMyInvitations(ctx context.Context, userID uuid.UUID) ([]models.Invitation, error) {
+	return s.repo.GetPendingInvitationsForUser(ctx, userID)
+}
+
+// AcceptInvitation consumes the token and adds the invitee to the team
+func (s *InvitationService) AcceptInvitation(ctx context.Context, token string, userID uuid.UUID) (*models.Invitation, error) {
+	invitation, err := s.getUsableInvitation(ctx, token, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roster := models.Roster{TeamID: invitation.TeamID, UserID: userID, Role: "MEMBER"}
+	if err := s.repo.AcceptInvitation(ctx, invitation, roster); err != nil {
+		return nil, err
+	}
+	invitation.Status = models.InvitationAccepted
+
+	if s.producer != nil {
+		err := s.producer.SendTeamEvent(
+			kafka.EventMemberAdded,
+			invitation.TeamID,
+			invitation.InvitedBy,
+			userID,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for member addition: %v", err)
+		}
+	}
+
+	return invitation, nil
+}
+
+// DeclineInvitation consumes the token without joining the team
+func (s *InvitationService) DeclineInvitation(ctx context.Context, token string, userID uuid.UUID) error {
+	invitation, err := s.getUsableInvitation(ctx, token, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdateInvitationStatus(ctx, invitation.ID, models.InvitationDeclined)
+}
+
+// CleanupExpired deletes pending invitations that have expired
+func (s *InvitationService) CleanupExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpiredInvitations(ctx, time.Now())
+}
+
+// RunExpiryCleanup periodically removes expired invitations until ctx is cancelled
+func (s *InvitationService) RunExpiryCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := s.CleanupExpired(ctx)
+			if err != nil {
+				log.Printf("Failed to clean up expired invitations: %v", err)
+				continue
+			}
+			if removed > 0 {
+				log.Printf("Removed %d expired invitations", removed)
+			}
+		}
+	}
+}
+
+// getUsableInvitation loads a pending, unexpired invitation addressed to userID
+func (s *InvitationService) getUsableInvitation(ctx context.Context, token string, userID uuid.UUID) (*models.Invitation, error) {
+	invitation, err := s.repo.GetInvitationByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invitation not found")
+		}
+		return nil, err
+	}
+	if invitation.InviteeID != userID {
+		return nil, errors.New("invitation not found")
+	}
+	if invitation.Status != models.InvitationPending {
+		return nil, errors.New("invitation has already been used")
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, errors.New("invitation has expired")
+	}
+	return invitation, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"go_service/internal/dto"
+	"go_service/internal/models"
+	"go_service/internal/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultPageSize applies when a list request does not set a limit
+const defaultPageSize = 20
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// pageCursor is the opaque cursor handed to clients; Sort guards against reusing it with another order
+type pageCursor struct {
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func encodePageCursor(c pageCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodePageCursor(s, sort string) (*pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort != sort {
+		return nil, errInvalidCursor
+	}
+	return &c, nil
+}
+
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	return limit
+}
+
+// folderSortColumns maps the public sort keys to folder columns
+var folderSortColumns = map[string]string{
+	"name":      "name",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+}
+
+// folderListOptions converts an asset list request into repository options.
+// One extra row is requested so the caller can tell whether another page exists.
+func folderListOptions(req *dto.AssetListRequest) (repositories.FolderListOptions, error) {
+	sort := req.Sort
+	if sort == "" {
+		sort = "name"
+	}
+	opts := repositories.FolderListOptions{
+		NameContains: req.NameContains,
+		SortColumn:   folderSortColumns[sort],
+		Desc:         req.Order == "desc",
+		Limit:        pageLimit(req.Limit) + 1,
+		Notes:        req.Notes,
+	}
+	if opts.Notes == "" {
+		opts.Notes = repositories.NotesFull
+	}
+
+	ownerID, err := parseOptionalID(req.OwnerID)
+	if err != nil {
+		return opts, err
+	}
+	opts.OwnerID = ownerID
+	if !req.UpdatedSince.IsZero() {
+		opts.UpdatedSince = &req.UpdatedSince
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodePageCursor(req.Cursor, sort+":"+req.Order)
+		if err != nil {
+			return opts, err
+		}
+		var value interface{} = cursor.Value
+		if sort != "name" {
+			t, err := time.Parse(time.RFC3339Nano, cursor.Value)
+			if err != nil {
+				return opts, errInvalidCursor
+			}
+			value = t
+		}
+		opts.After = &repositories.KeysetCursor{Value: value, ID: cursor.ID}
+	}
+	return opts, nil
+}
+
+// folderPage trims the extra row fetched by folderListOptions and builds the next cursor
+func folderPage(folders []models.Folder, req *dto.AssetListRequest) ([]models.Folder, string) {
+	limit := pageLimit(req.Limit)
+	if len(folders) <= limit {
+		return folders, ""
+	}
+	folders = folders[:limit]
+	last := folders[limit-1]
+
+	sort := req.Sort
+	if sort == "" {
+		sort = "name"
+	}
+	cursor := pageCursor{Sort: sort + ":" + req.Order, ID: last.ID}
+	switch sort {
+	case "createdAt":
+		cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	case "updatedAt":
+		cursor.Value = last.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = last.Name
+	}
+	return folders, encodePageCursor(cursor)
+}
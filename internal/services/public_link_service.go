@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go_service/internal/dto"
+	"go_service/internal/models"
+	"go_service/internal/policy"
+	"go_service/internal/repositories"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPublicLinkUnavailable hides whether a link never existed, expired, was revoked or lost its resource
+	ErrPublicLinkUnavailable = errors.New("public link is not available")
+	ErrPublicLinkPassword    = errors.New("public link password is missing or incorrect")
+)
+
+type IPublicLinkService interface {
+	CreateLink(ctx context.Context, resourceID, userID uuid.UUID, resourceType string, req *dto.CreatePublicLinkRequest) (*models.PublicLink, error)
+	GetLinks(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) ([]models.PublicLink, error)
+	RevokeLink(ctx context.Context, linkID, userID uuid.UUID) error
+	OpenLink(ctx context.Context, token, password string) (*dto.PublicLinkContent, error)
+	OpenLinkFolder(ctx context.Context, token, password string, folderID uuid.UUID) (*dto.PublicLinkContent, error)
+	OpenLinkNote(ctx context.Context, token, password string, noteID uuid.UUID) (*dto.PublicLinkContent, error)
+}
+
+type PublicLinkService struct {
+	repo   repositories.IPublicLinkRepository
+	assets *AssetService
+}
+
+// NewPublicLinkService reuses the asset service so links follow the same ownership rules as shares
+func NewPublicLinkService(repo repositories.IPublicLinkRepository, assets *AssetService) *PublicLinkService {
+	return &PublicLinkService{
+		repo:   repo,
+		assets: assets,
+	}
+}
+
+// CreateLink mints a link for a folder or note; only the owner or a team manager may do so
+func (s *PublicLinkService) CreateLink(ctx context.Context, resourceID, userID uuid.UUID, resourceType string, req *dto.CreatePublicLinkRequest) (*models.PublicLink, error) {
+	target, err := s.assets.getShareTarget(ctx, resourceID, userID, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assets.ensureTeamWritable(ctx, target.TeamID); err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("expiresAt must be in the future")
+	}
+
+	token, err := generateToken(32)
+	if err != nil {
+		return nil, err
+	}
+	link := &models.PublicLink{
+		Token:        token,
+		ResourceID:   resourceID,
+		ResourceType: resourceType,
+		TeamID:       target.TeamID,
+		CreatedBy:    userID,
+		ExpiresAt:    req.ExpiresAt,
+	}
+	if req.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+		link.PasswordHash = string(hash)
+		link.HasPassword = true
+	}
+
+	if err := s.repo.CreateLink(ctx, link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// GetLinks lists the links of a folder or note, including revoked and expired ones
+func (s *PublicLinkService) GetLinks(ctx context.Context, resourceID, userID uuid.UUID, resourceType string) ([]models.PublicLink, error) {
+	if _, err := s.assets.getShareTarget(ctx, resourceID, userID, resourceType); err != nil {
+		return nil, err
+	}
+	return s.repo.GetResourceLinks(ctx, resourceID, resourceType)
+}
+
+// RevokeLink disables a link; its creator, the resource owner and team managers may revoke it
+func (s *PublicLinkService) RevokeLink(ctx context.Context, linkID, userID uuid.UUID) error {
+	link, err := s.repo.GetLinkByID(ctx, linkID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("public link not found")
+		}
+		return err
+	}
+	if link.CreatedBy != userID {
+		if _, err := s.assets.getShareTarget(ctx, link.ResourceID, userID, link.ResourceType); err != nil {
+			return err
+		}
+	}
+	return s.repo.DisableLink(ctx, link.ID, models.LinkRevoked)
+}
+
+// OpenLink serves the note or folder a link points at
+func (s *PublicLinkService) OpenLink(ctx context.Context, token, password string) (*dto.PublicLinkContent, error) {
+	link, err := s.openLink(ctx, token, password)
+	if err != nil {
+		return nil, err
+	}
+	if link.ResourceType == "note" {
+		return s.noteContent(ctx, link, link.ResourceID)
+	}
+	return s.folderContent(ctx, link, link.ResourceID)
+}
+
+// OpenLinkFolder serves a sub-folder of a shared folder
+func (s *PublicLinkService) OpenLinkFolder(ctx context.Context, token, password string, folderID uuid.UUID) (*dto.PublicLinkContent, error) {
+	link, err := s.openLink(ctx, token, password)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkInLinkedFolder(ctx, link, folderID); err != nil {
+		return nil, err
+	}
+	return s.folderContent(ctx, link, folderID)
+}
+
+// OpenLinkNote serves the linked note, or any note below a linked folder
+func (s *PublicLinkService) OpenLinkNote(ctx context.Context, token, password string, noteID uuid.UUID) (*dto.PublicLinkContent, error) {
+	link, err := s.openLink(ctx, token, password)
+	if err != nil {
+		return nil, err
+	}
+	if link.ResourceType == "note" {
+		if noteID != link.ResourceID {
+			return nil, ErrPublicLinkUnavailable
+		}
+		return s.noteContent(ctx, link, noteID)
+	}
+
+	note, err := s.assets.assetRepo.GetNoteByID(ctx, noteID)
+	if err != nil {
+		return nil, linkLookupError(err)
+	}
+	if err := s.checkInLinkedFolder(ctx, link, note.FolderID); err != nil {
+		return nil, err
+	}
+	return s.noteContent(ctx, link, noteID)
+}
+
+// openLink validates the token and password, and disables the link if its creator could no longer create it
+func (s *PublicLinkService) openLink(ctx context.Context, token, password string) (*models.PublicLink, error) {
+	link, err := s.repo.GetLinkByToken(ctx, token)
+	if err != nil {
+		return nil, linkLookupError(err)
+	}
+	if link.DisabledAt != nil || (link.ExpiresAt != nil && !link.ExpiresAt.After(time.Now())) {
+		return nil, ErrPublicLinkUnavailable
+	}
+	if link.PasswordHash != "" && bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)) != nil {
+		return nil, ErrPublicLinkPassword
+	}
+
+	// Trashed resources make the link unavailable without disabling it, so a restore brings it back
+	var resource policy.Resource
+	if link.ResourceType == "note" {
+		note, err := s.assets.assetRepo.GetNoteByID(ctx, link.ResourceID)
+		if err != nil {
+			return nil, linkLookupError(err)
+		}
+		folder, err := s.assets.assetRepo.GetFolderByID(ctx, note.FolderID)
+		if err != nil {
+			return nil, linkLookupError(err)
+		}
+		resource = policy.NoteResource(note, folder)
+	} else {
+		folder, err := s.assets.assetRepo.GetFolderByID(ctx, link.ResourceID)
+		if err != nil {
+			return nil, linkLookupError(err)
+		}
+		resource = policy.FolderResource(folder)
+	}
+	// Creating a link needs share rights, so a creator downgraded below that loses the link as well
+	allowed, err := s.assets.policy.Can(ctx, link.CreatedBy, policy.Share, resource)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		if err := s.repo.DisableLink(ctx, link.ID, models.LinkCreatorLostAccess); err != nil {
+			return nil, err
+		}
+		return nil, ErrPublicLinkUnavailable
+	}
+	return link, nil
+}
+
+// checkInLinkedFolder rejects folders outside the subtree of a folder link
+func (s *PublicLinkService) checkInLinkedFolder(ctx context.Context, link *models.PublicLink, folderID uuid.UUID) error {
+	if link.ResourceType != "folder" {
+		return ErrPublicLinkUnavailable
+	}
+	ancestors, err := s.assets.assetRepo.GetFolderAncestors(ctx, folderID)
+	if err != nil {
+		return err
+	}
+	// Ancestors run from the top-level folder down, so every folder below the linked one must be live
+	for i, ancestor := range ancestors {
+		if ancestor.ID != link.ResourceID {
+			continue
+		}
+		for _, below := range ancestors[i:] {
+			if below.DeletedAt.Valid {
+				return ErrPublicLinkUnavailable
+			}
+		}
+		return nil
+	}
+	return ErrPublicLinkUnavailable
+}
+
+func (s *PublicLinkService) noteContent(ctx context.Context, link *models.PublicLink, noteID uuid.UUID) (*dto.PublicLinkContent, error) {
+	note, err := s.assets.assetRepo.GetNoteByID(ctx, noteID)
+	if err != nil {
+		return nil, linkLookupError(err)
+	}
+	s.recordView(ctx, link)
+	return &dto.PublicLinkContent{
+		ResourceType: "note",
+		ExpiresAt:    link.ExpiresAt,
+		Note: &dto.PublicNote{
+			ID:        note.ID,
+			Title:     note.Title,
+			Content:   note.Content,
+			UpdatedAt: note.UpdatedAt,
+		},
+	}, nil
+}
+
+func (s *PublicLinkService) folderContent(ctx context.Context, link *models.PublicLink, folderID uuid.UUID) (*dto.PublicLinkContent, error) {
+	folder, err := s.assets.assetRepo.GetFolderByID(ctx, folderID)
+	if err != nil {
+		return nil, linkLookupError(err)
+	}
+	subtree, err := s.assets.assetRepo.GetFolderSubtree(ctx, folderID, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	public := &dto.PublicFolder{
+		ID:      folder.ID,
+		Name:    folder.Name,
+		Folders: []dto.PublicFolderSummary{},
+		Notes:   make([]dto.PublicNoteSummary, 0, len(folder.Notes)),
+	}
+	for _, child := range subtree {
+		if child.ID != folder.ID {
+			public.Folders = append(public.Folders, dto.PublicFolderSummary{ID: child.ID, Name: child.Name})
+		}
+	}
+	for _, note := range folder.Notes {
+		public.Notes = append(public.Notes, dto.PublicNoteSummary{ID: note.ID, Title: note.Title, UpdatedAt: note.UpdatedAt})
+	}
+
+	s.recordView(ctx, link)
+	return &dto.PublicLinkContent{
+		ResourceType: "folder",
+		ExpiresAt:    link.ExpiresAt,
+		Folder:       public,
+	}, nil
+}
+
+// recordView counts a successful visit; a failed counter update should not block reading
+func (s *PublicLinkService) recordView(ctx context.Context, link *models.PublicLink) {
+	if err := s.repo.RecordView(ctx, link.ID); err != nil {
+		log.Printf("Failed to record view of public link %s: %v", link.ID, err)
+	}
+}
+
+func linkLookupError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPublicLinkUnavailable
+	}
+	return err
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go_service/pkg/redisclient"
+
+	"github.com/google/uuid"
+)
+
+type IStreamService interface {
+	SubscribeNote(ctx context.Context, noteID, userID uuid.UUID) (*Subscription, error)
+	SubscribeFolder(ctx context.Context, folderID, userID uuid.UUID) (*Subscription, error)
+}
+
+// Subscription is a live feed of the changes to one folder or note.
+// Close must be called once the subscriber goes away.
+type Subscription struct {
+	ResourceID uuid.UUID
+	Changes    <-chan redisclient.Change
+	Close      func()
+
+	authorize func(ctx context.Context) error
+}
+
+// Authorize repeats the access check the subscription was opened with, so streams can end once
+// the subscriber loses access
+func (sub *Subscription) Authorize(ctx context.Context) error {
+	return sub.authorize(ctx)
+}
+
+type StreamService struct {
+	assets  *AssetService
+	changes *redisclient.ChangeFeed
+}
+
+// NewStreamService reuses the asset service so subscriptions need the same access as reads
+func NewStreamService(assets *AssetService, changes *redisclient.ChangeFeed) *StreamService {
+	return &StreamService{
+		assets:  assets,
+		changes: changes,
+	}
+}
+
+// SubscribeNote streams the changes to a note the user can read
+func (s *StreamService) SubscribeNote(ctx context.Context, noteID, userID uuid.UUID) (*Subscription, error) {
+	authorize := func(ctx context.Context) error {
+		_, err := s.assets.GetNote(ctx, noteID, userID)
+		return err
+	}
+	return s.subscribe(ctx, noteID, redisclient.NoteChannel(noteID), authorize)
+}
+
+// SubscribeFolder streams the changes to a folder the user can read, its notes and its direct sub-folders
+func (s *StreamService) SubscribeFolder(ctx context.Context, folderID, userID uuid.UUID) (*Subscription, error) {
+	authorize := func(ctx context.Context) error {
+		_, err := s.assets.GetFolder(ctx, folderID, userID)
+		return err
+	}
+	return s.subscribe(ctx, folderID, redisclient.FolderChannel(folderID), authorize)
+}
+
+func (s *StreamService) subscribe(ctx context.Context, resourceID uuid.UUID, channel string, authorize func(ctx context.Context) error) (*Subscription, error) {
+	if s.changes == nil {
+		return nil, errors.New("live updates are not available")
+	}
+	if err := authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	changes, cancel, err := s.changes.Subscribe(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+	return &Subscription{
+		ResourceID: resourceID,
+		Changes:    changes,
+		Close:      cancel,
+		authorize:  authorize,
+	}, nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go_service/internal/dto"
+	"go_service/internal/models"
+	"go_service/internal/repositories"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ITagService interface {
+	CreateTag(ctx context.Context, teamID, userID uuid.UUID, req *dto.CreateTagRequest) (*models.Tag, error)
+	GetTeamTags(ctx context.Context, teamID, userID uuid.UUID) ([]models.Tag, error)
+	UpdateTag(ctx context.Context, teamID, tagID, userID uuid.UUID, req *dto.UpdateTagRequest) (*models.Tag, error)
+	DeleteTag(ctx context.Context, teamID, tagID, userID uuid.UUID) error
+	GetTagCounts(ctx context.Context, teamID, userID uuid.UUID) ([]models.TagCount, error)
+}
+
+type TagService struct {
+	tagRepo  repositories.ITagRepository
+	teamRepo repositories.ITeamRepository
+}
+
+func NewTagService(tagRepo repositories.ITagRepository, teamRepo repositories.ITeamRepository) *TagService {
+	return &TagService{
+		tagRepo:  tagRepo,
+		teamRepo: teamRepo,
+	}
+}
+
+// normalizeTagName makes tag names case-insensitive
+func normalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// parseTagFilter turns a comma separated list of tag names into a repository filter
+func parseTagFilter(tags, match string) repositories.TagFilter {
+	filter := repositories.TagFilter{MatchAll: match == "all"}
+	seen := map[string]bool{}
+	for _, name := range strings.Split(tags, ",") {
+		name = normalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		filter.Names = append(filter.Names, name)
+	}
+	return filter
+}
+
+// CreateTag adds a tag to the team; any team member may create tags
+func (s *TagService) CreateTag(ctx context.Context, teamID, userID uuid.UUID, req *dto.CreateTagRequest) (*models.Tag, error) {
+	isMember, err := s.teamRepo.IsUserInTeam(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("user is not a member of this team")
+	}
+
+	name := normalizeTagName(req.Name)
+	if name == "" {
+		return nil, errors.New("tag name is required")
+	}
+	if _, err := s.tagRepo.GetTagByName(ctx, teamID, name); err == nil {
+		return nil, errors.New("tag already exists in this team")
+	}
+
+	tag := &models.Tag{
+		TeamID:    teamID,
+		Name:      name,
+		Color:     req.Color,
+		CreatedBy: userID,
+	}
+	if err := s.tagRepo.CreateTag(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// GetTeamTags lists the tags of a team for its members
+func (s *TagService) GetTeamTags(ctx context.Context, teamID, userID uuid.UUID) ([]models.Tag, error) {
+	isMember, err := s.teamRepo.IsUserInTeam(ctx, teamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("user is not a member of this team")
+	}
+	return s.tagRepo.GetTeamTags(ctx, teamID)
+}
+
+// getEditableTag loads a team tag that the user created or, as a manager, may edit
+func (s *TagService) getEditableTag(ctx context.Context, teamID, tagID, userID uuid.UUID) (*models.Tag, error) {
+	tag, err := s.tagRepo.GetTagByID(ctx, tagID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag not found")
+		}
+		return nil, err
+	}
+	if tag.TeamID != teamID {
+		return nil, errors.New("tag not found")
+	}
+
+	if tag.CreatedBy != userID {
+		role, err := s.teamRepo.GetUserRoleInTeam(ctx, teamID, userID)
+		if err != nil || (role != "MANAGER" && role != "MAIN_MANAGER") {
+			return nil, errors.New("only the tag creator or a team manager can change this tag")
+		}
+	}
+	return tag, nil
+}
+
+// UpdateTag renames or recolors a tag
+func (s *TagService) UpdateTag(ctx context.Context, teamID, tagID, userID uuid.UUID, req *dto.UpdateTagRequest) (*models.Tag, error) {
+	tag, err := s.getEditableTag(ctx, teamID, tagID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		name := normalizeTagName(req.Name)
+		if name != tag.Name {
+			if _, err := s.tagRepo.GetTagByName(ctx, teamID, name); err == nil {
+				return nil, errors.New("tag already exists in this team")
+			}
+			tag.Name = name
+		}
+	}
+	if req.Color != "" {
+		tag.Color = req.Color
+	}
+
+	if err := s.tagRepo.UpdateTag(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// DeleteTag deletes a tag and detaches it from all assets
+func (s *TagService) DeleteTag(ctx context.Context, teamID, tagID, userID uuid.UUID) error {
+	if _, err := s.getEditableTag(ctx, teamID, tagID, userID); err != nil {
+		return err
+	}
+	return s.tagRepo.DeleteTag(ctx, tagID)
+}
+
+// GetTagCounts reports per-tag usage of a team (managers only)
+func (s *TagService) GetTagCounts(ctx context.Context, teamID, userID uuid.UUID) ([]models.TagCount, error) {
+	role, err := s.teamRepo.GetUserRoleInTeam(ctx, teamID, userID)
+	if err != nil || (role != "MANAGER" && role != "MAIN_MANAGER") {
+		return nil, errors.New("you are not a manager")
+	}
+	return s.tagRepo.GetTagCounts(ctx, teamID)
+}
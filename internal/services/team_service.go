@@ -3,22 +3,32 @@ package services
 import (
 	"context"
 	"errors"
+	"go_service/internal/dto"
 	"go_service/internal/models"
 	"go_service/internal/repositories"
 	"go_service/pkg/kafka"
 	"go_service/pkg/redisclient"
 	"log"
+	"sort"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
 type ITeamService interface {
-	CreateTeam(ctx context.Context, teamName string, userIDs []uuid.UUID, creatorID uuid.UUID) (*models.Team, []uuid.UUID, error)
-	AddMembersToTeam(ctx context.Context, teamID uuid.UUID, userIDs []uuid.UUID, currentUserID uuid.UUID) (int, int, []uuid.UUID, error)
-	GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]models.User, error)
-	RemoveMemberFromTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, currentUserID uuid.UUID) error
-	RemoveManagerFromTeam(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID, currentUserID uuid.UUID) error
+	CreateTeam(ctx context.Context, teamName string, creatorID uuid.UUID) (*models.Team, error)
+	GetTeamMembers(ctx context.Context, teamID uuid.UUID, req *dto.PageRequest) (*dto.Page[models.User], error)
+	RemoveMemberFromTeam(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, currentUserID uuid.UUID, req *dto.RemoveMemberReq) (*dto.OffboardingReport, error)
+	RemoveManagerFromTeam(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID, currentUserID uuid.UUID, req *dto.RemoveMemberReq) (*dto.OffboardingReport, error)
+	AddManagerToTeam(ctx context.Context, teamID uuid.UUID, memberID uuid.UUID, currentUserID uuid.UUID) error
+	DemoteManager(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID, currentUserID uuid.UUID) error
+	TransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID, currentUserID uuid.UUID) error
+	ForceTransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID, adminID uuid.UUID) error
+	GetUserTeams(ctx context.Context, userID uuid.UUID) ([]models.Team, error)
+	GetTeam(ctx context.Context, teamID uuid.UUID, currentUserID uuid.UUID) (*models.Team, error)
+	RenameTeam(ctx context.Context, teamID uuid.UUID, teamName string, currentUserID uuid.UUID) (*models.Team, error)
+	SetTeamArchived(ctx context.Context, teamID uuid.UUID, archived bool, currentUserID uuid.UUID) (*models.Team, error)
+	DeleteTeam(ctx context.Context, teamID uuid.UUID, currentUserID uuid.UUID) error
 }
 
 type TeamService struct {
@@ -37,108 +47,89 @@ func NewTeamService(repo repositories.ITeamRepository, producer *kafka.Producer,
 	}
 }
 
-// Creates a new team and adds members
-func (s *TeamService) CreateTeam(ctx context.Context, teamName string, userIDs []uuid.UUID, creatorID uuid.UUID) (*models.Team, []uuid.UUID, error) {
+// Creates a new team with the creator as its main manager; everyone else joins through invitations
+func (s *TeamService) CreateTeam(ctx context.Context, teamName string, creatorID uuid.UUID) (*models.Team, error) {
 	team := &models.Team{TeamName: teamName}
 	if err := s.repo.CreateTeam(ctx, team); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Add creator as leader
 	leaderRoster := models.Roster{TeamID: team.ID, UserID: creatorID, Role: "MAIN_MANAGER"}
 	if err := s.repo.AddMemberToTeam(ctx, leaderRoster); err != nil {
-		return nil, nil, err
-	}
-
-	if len(userIDs) == 0 {
-		return team, nil, nil
+		return nil, err
 	}
 
-	_, _, failedMembers, err := s.AddMembersToTeam(ctx, team.ID, userIDs, creatorID)
-	if err != nil {
-		log.Printf("Error adding members to team: %v", err)
+	if s.producer != nil {
+		err := s.producer.SendTeamEvent(
+			kafka.EventTeamCreated,
+			team.ID,
+			creatorID,
+			creatorID,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for team creation: %v", err)
+		}
 	}
 
-	return team, failedMembers, nil
+	return team, nil
 }
 
-// adds members to a team with validation
-func (s *TeamService) AddMembersToTeam(ctx context.Context, teamID uuid.UUID, userIDs []uuid.UUID, currentUserID uuid.UUID) (int, int, []uuid.UUID, error) {
-	// Sửa lỗi điều kiện kiểm tra quyền
-	currentUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, currentUserID)
-	if err != nil || (currentUserRole != "MANAGER" && currentUserRole != "MAIN_MANAGER") {
-		return 0, len(userIDs), userIDs, errors.New("you are not a manager")
-	}
-
-	addedCount := 0
-	failedCount := 0
-	failedMembers := []uuid.UUID{}
-	validRosters := make([]models.Roster, 0, len(userIDs))
-
-	// Lấy danh sách members hiện có để kiểm tra nhanh
-	existingMembers, err := s.repo.GetTeamMembers(ctx, teamID)
+// GetTeamMembers returns a page of team members ordered by user ID.
+// Only the users of the requested page are resolved through the user service.
+func (s *TeamService) GetTeamMembers(ctx context.Context, teamID uuid.UUID, req *dto.PageRequest) (*dto.Page[models.User], error) {
+	userIDs, err := s.getTeamMemberIDs(ctx, teamID)
 	if err != nil {
-		return 0, len(userIDs), userIDs, err
-	}
-
-	existingMap := make(map[uuid.UUID]bool)
-	for _, member := range existingMembers {
-		existingMap[member.UserID] = true
+		return nil, err
 	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i].String() < userIDs[j].String() })
 
-	for _, userID := range userIDs {
-		if existingMap[userID] {
-			failedMembers = append(failedMembers, userID)
-			failedCount++
-			continue
+	if req.Cursor != "" {
+		cursor, err := decodePageCursor(req.Cursor, "userId")
+		if err != nil {
+			return nil, err
 		}
-
-		validRosters = append(validRosters, models.Roster{TeamID: teamID, UserID: userID, Role: "MEMBER"})
+		after := cursor.ID.String()
+		start := sort.Search(len(userIDs), func(i int) bool { return userIDs[i].String() > after })
+		userIDs = userIDs[start:]
 	}
 
-	// Nếu không có user hợp lệ để thêm
-	if len(validRosters) == 0 {
-		return 0, failedCount, failedMembers, nil
+	page := &dto.Page[models.User]{Items: []models.User{}}
+	limit := pageLimit(req.Limit)
+	if len(userIDs) > limit {
+		userIDs = userIDs[:limit]
+		page.NextCursor = encodePageCursor(pageCursor{Sort: "userId", ID: userIDs[limit-1]})
+	}
+	if len(userIDs) == 0 {
+		return page, nil
 	}
 
-	for _, roster := range validRosters {
-		if err := s.repo.AddMemberToTeam(ctx, roster); err != nil {
-			failedMembers = append(failedMembers, roster.UserID)
-			failedCount++
-		}
+	// Fetch user details from user service
+	users, err := s.userService.GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	//gửi event kafka
-	if s.producer != nil {
-		for _, roster := range validRosters {
-			err := s.producer.SendTeamEvent(
-				kafka.EventMemberAdded,
-				teamID,
-				currentUserID,
-				roster.UserID,
-			)
-			if err != nil {
-				log.Printf("Failed to send Kafka event for member addition: %v", err)
-				// Continue processing even if Kafka event fails
-			}
+	// Keep the page order regardless of how the user service sorts its answer
+	byID := make(map[uuid.UUID]models.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+	for _, id := range userIDs {
+		if user, ok := byID[id]; ok {
+			page.Items = append(page.Items, user)
 		}
 	}
-
-	addedCount = len(validRosters)
-	return addedCount, failedCount, failedMembers, nil
+	return page, nil
 }
-func (s *TeamService) GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]models.User, error) {
+
+// getTeamMemberIDs reads the member IDs from the Redis cache, falling back to the database
+func (s *TeamService) getTeamMemberIDs(ctx context.Context, teamID uuid.UUID) ([]uuid.UUID, error) {
 	// Try to get from Redis cache first
 	if s.redisClient != nil {
 		cachedMembers, err := s.redisClient.GetMembers(ctx, teamID)
 		if err == nil && len(cachedMembers) > 0 {
-			// Fetch user details from user service
-			users, err := s.userService.GetUsersByIDs(ctx, cachedMembers)
-			if err == nil && len(users) > 0 {
-				log.Printf("Fetched user details for cached members: %v", users)
-				return users, nil
-			}
-			log.Printf("Failed to fetch user details for cached members: %v", err)
+			return cachedMembers, nil
 		} else if err != nil && err != redis.Nil {
 			log.Printf("Redis error: %v", err)
 		}
@@ -158,46 +149,179 @@ func (s *TeamService) GetTeamMembers(ctx context.Context, teamID uuid.UUID) ([]m
 			log.Printf("Failed to update Redis cache: %v", err)
 		}
 	}
-	// Fetch user details from user service
-	users, err := s.userService.GetUsersByIDs(ctx, userIDs)
-	if err != nil {
-		return nil, err
-	}
-
-	return users, nil
+	return userIDs, nil
 }
 
-func (s *TeamService) RemoveMemberFromTeam(ctx context.Context, teamID uuid.UUID, targetID uuid.UUID, currentUserID uuid.UUID) error {
+func (s *TeamService) RemoveMemberFromTeam(ctx context.Context, teamID uuid.UUID, targetID uuid.UUID, currentUserID uuid.UUID, req *dto.RemoveMemberReq) (*dto.OffboardingReport, error) {
 	// Check if current user is a manager
 	currentUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, currentUserID)
 	if err != nil || (currentUserRole != "MANAGER" && currentUserRole != "MAIN_MANAGER") {
-		return errors.New("you are not a manager")
+		return nil, errors.New("you are not a manager")
 	}
 	targetUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, targetID)
 	if err != nil || targetUserRole == "MAIN_MANAGER" || targetUserRole == "MANAGER" {
-		return errors.New("cannot remove a manager or main manager")
+		return nil, errors.New("cannot remove a manager or main manager")
+	}
+
+	plan, err := s.offboardingPlan(ctx, teamID, targetID, req)
+	if err != nil {
+		return nil, err
+	}
+	return s.offboard(ctx, teamID, targetID, currentUserID, plan)
+}
+
+func (s *TeamService) RemoveManagerFromTeam(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID, currentUserID uuid.UUID, req *dto.RemoveMemberReq) (*dto.OffboardingReport, error) {
+	// Check if current user is a MAIN_MANAGER
+	if managerID == currentUserID {
+		return nil, errors.New("you cannot remove yourself")
+	}
+
+	currentUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, currentUserID)
+	if err != nil || currentUserRole != "MAIN_MANAGER" {
+		return nil, errors.New("you are not the main manager")
+	}
+	targetUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, managerID)
+	if err != nil || targetUserRole != "MANAGER" {
+		return nil, errors.New("target user is not a manager")
 	}
 
+	plan, err := s.offboardingPlan(ctx, teamID, managerID, req)
+	if err != nil {
+		return nil, err
+	}
+	// The manager also leaves the team, so offboard sends MEMBER_REMOVED for the member cache too
+	report, err := s.offboard(ctx, teamID, managerID, currentUserID, plan)
+	if err != nil {
+		return nil, err
+	}
 	if s.producer != nil {
 		err := s.producer.SendTeamEvent(
-			kafka.EventMemberRemoved,
+			kafka.EventManagerRemoved,
 			teamID,
 			currentUserID,
-			targetID,
+			managerID,
 		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for manager removal: %v", err)
+		}
+	}
+	return report, nil
+}
+
+// offboardingPlan resolves the offboarding policy for a user leaving the team. Assets go to the
+// requested manager, or to the MAIN_MANAGER when none is given.
+func (s *TeamService) offboardingPlan(ctx context.Context, teamID uuid.UUID, leaverID uuid.UUID, req *dto.RemoveMemberReq) (repositories.OffboardingPlan, error) {
+	plan := repositories.OffboardingPlan{RevokeShares: req.Shares != "keep"}
+	if req.Assets == "keep" {
+		if req.ReassignTo != "" {
+			return plan, errors.New("reassignTo cannot be used when keeping the assets")
+		}
+		return plan, nil
+	}
+
+	var newOwnerID uuid.UUID
+	if req.ReassignTo != "" {
+		id, err := uuid.Parse(req.ReassignTo)
+		if err != nil {
+			return plan, err
+		}
+		role, err := s.repo.GetUserRoleInTeam(ctx, teamID, id)
+		if err != nil || (role != "MANAGER" && role != "MAIN_MANAGER") {
+			return plan, errors.New("assets can only be reassigned to a manager of the team")
+		}
+		newOwnerID = id
+	} else {
+		ownerID, err := s.repo.GetTeamOwnerID(ctx, teamID)
+		if err != nil {
+			return plan, err
+		}
+		newOwnerID = ownerID
+	}
+	if newOwnerID == leaverID {
+		return plan, errors.New("assets cannot be reassigned to the user being removed")
+	}
+	plan.ReassignTo = &newOwnerID
+	return plan, nil
+}
+
+// offboard removes a user from the team under the given plan and reports the changes, also in the MEMBER_REMOVED event
+func (s *TeamService) offboard(ctx context.Context, teamID uuid.UUID, targetID uuid.UUID, currentUserID uuid.UUID, plan repositories.OffboardingPlan) (*dto.OffboardingReport, error) {
+	result, err := s.repo.RemoveMemberFromTeam(ctx, teamID, targetID, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.OffboardingReport{
+		ReassignedTo:      plan.ReassignTo,
+		ReassignedFolders: result.ReassignedFolders,
+		ReassignedNotes:   result.ReassignedNotes,
+		RevokedShares:     result.RevokedShares,
+	}
+
+	if s.producer != nil {
+		revoked := make([]kafka.RevokedShare, 0, len(result.RevokedShares))
+		for _, share := range result.RevokedShares {
+			revoked = append(revoked, kafka.RevokedShare{
+				ShareID:      share.ID,
+				ResourceID:   share.ResourceID,
+				ResourceType: share.ResourceType,
+				Permission:   share.Permission,
+			})
+		}
+		err := s.producer.SendMemberRemovedEvent(teamID, currentUserID, targetID, &kafka.Offboarding{
+			ReassignedTo:      report.ReassignedTo,
+			ReassignedFolders: report.ReassignedFolders,
+			ReassignedNotes:   report.ReassignedNotes,
+			RevokedShares:     revoked,
+		})
 		if err != nil {
 			log.Printf("Failed to send Kafka event for member removal: %v", err)
 		}
 	}
+	return report, nil
+}
 
-	return s.repo.RemoveMemberFromTeam(ctx, teamID, targetID)
+// AddManagerToTeam promotes an existing MEMBER to MANAGER (only MAIN_MANAGER can do this)
+func (s *TeamService) AddManagerToTeam(ctx context.Context, teamID uuid.UUID, memberID uuid.UUID, currentUserID uuid.UUID) error {
+	if memberID == currentUserID {
+		return errors.New("you cannot change your own role")
+	}
 
+	currentUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, currentUserID)
+	if err != nil || currentUserRole != "MAIN_MANAGER" {
+		return errors.New("you are not the main manager")
+	}
+	targetUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, memberID)
+	if err != nil {
+		return errors.New("target user is not a member of this team")
+	}
+	if targetUserRole != "MEMBER" {
+		return errors.New("target user is already a manager")
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, teamID, memberID, "MANAGER"); err != nil {
+		return err
+	}
+
+	if s.producer != nil {
+		err := s.producer.SendTeamEvent(
+			kafka.EventManagerAdded,
+			teamID,
+			currentUserID,
+			memberID,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for manager addition: %v", err)
+		}
+	}
+
+	return nil
 }
 
-func (s *TeamService) RemoveManagerFromTeam(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID, currentUserID uuid.UUID) error {
-	// Check if current user is a MAIN_MANAGER
+// DemoteManager turns a MANAGER back into a MEMBER without removing them from the team
+func (s *TeamService) DemoteManager(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID, currentUserID uuid.UUID) error {
 	if managerID == currentUserID {
-		return errors.New("you cannot remove yourself")
+		return errors.New("you cannot change your own role")
 	}
 
 	currentUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, currentUserID)
@@ -208,6 +332,11 @@ func (s *TeamService) RemoveManagerFromTeam(ctx context.Context, teamID uuid.UUI
 	if err != nil || targetUserRole != "MANAGER" {
 		return errors.New("target user is not a manager")
 	}
+
+	if err := s.repo.UpdateMemberRole(ctx, teamID, managerID, "MEMBER"); err != nil {
+		return err
+	}
+
 	if s.producer != nil {
 		err := s.producer.SendTeamEvent(
 			kafka.EventManagerRemoved,
@@ -216,9 +345,224 @@ func (s *TeamService) RemoveManagerFromTeam(ctx context.Context, teamID uuid.UUI
 			managerID,
 		)
 		if err != nil {
-			log.Printf("Failed to send Kafka event for manager removal: %v", err)
+			log.Printf("Failed to send Kafka event for manager demotion: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// TransferOwnership hands MAIN_MANAGER to another member and demotes the current owner to MANAGER
+func (s *TeamService) TransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID, currentUserID uuid.UUID) error {
+	if newOwnerID == currentUserID {
+		return errors.New("you already own this team")
+	}
+
+	currentUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, currentUserID)
+	if err != nil || currentUserRole != "MAIN_MANAGER" {
+		return errors.New("you are not the main manager")
+	}
+	isMember, err := s.repo.IsUserInTeam(ctx, teamID, newOwnerID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("new owner is not a member of this team")
+	}
+
+	if err := s.repo.TransferOwnership(ctx, teamID, currentUserID, newOwnerID, false); err != nil {
+		return err
+	}
+
+	if s.producer != nil {
+		err := s.producer.SendTeamEvent(
+			kafka.EventOwnerChanged,
+			teamID,
+			currentUserID,
+			newOwnerID,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for ownership transfer: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// ForceTransferOwnership lets an admin reassign a team whose owner account no longer exists.
+// The orphaned owner is removed from the roster instead of being demoted.
+func (s *TeamService) ForceTransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID, adminID uuid.UUID) error {
+	ownerID, err := s.repo.GetTeamOwnerID(ctx, teamID)
+	if err != nil {
+		return errors.New("team has no main manager")
+	}
+	if ownerID == newOwnerID {
+		return errors.New("user already owns this team")
+	}
+
+	exists, err := s.userService.UserExists(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("current owner still exists, ask them to transfer ownership")
+	}
+
+	isMember, err := s.repo.IsUserInTeam(ctx, teamID, newOwnerID)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return errors.New("new owner is not a member of this team")
+	}
+
+	if err := s.repo.TransferOwnership(ctx, teamID, ownerID, newOwnerID, true); err != nil {
+		return err
+	}
+
+	if s.producer != nil {
+		err := s.producer.SendTeamEvent(
+			kafka.EventOwnerChanged,
+			teamID,
+			adminID,
+			newOwnerID,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for ownership transfer: %v", err)
+		}
+		err = s.producer.SendTeamEvent(
+			kafka.EventMemberRemoved,
+			teamID,
+			adminID,
+			ownerID,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for member removal: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// GetUserTeams lists every team the user belongs to
+func (s *TeamService) GetUserTeams(ctx context.Context, userID uuid.UUID) ([]models.Team, error) {
+	return s.repo.GetTeamsByUserID(ctx, userID)
+}
+
+// GetTeam returns a team if the current user is one of its members
+func (s *TeamService) GetTeam(ctx context.Context, teamID uuid.UUID, currentUserID uuid.UUID) (*models.Team, error) {
+	isMember, err := s.repo.IsUserInTeam(ctx, teamID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return nil, errors.New("you are not a member of this team")
+	}
+
+	return s.repo.GetTeamByID(ctx, teamID)
+}
+
+// RenameTeam changes the team name (managers only, names are unique)
+func (s *TeamService) RenameTeam(ctx context.Context, teamID uuid.UUID, teamName string, currentUserID uuid.UUID) (*models.Team, error) {
+	currentUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, currentUserID)
+	if err != nil || (currentUserRole != "MANAGER" && currentUserRole != "MAIN_MANAGER") {
+		return nil, errors.New("you are not a manager")
+	}
+
+	team, err := s.repo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.Archived {
+		return nil, errors.New("team is archived")
+	}
+	if team.TeamName == teamName {
+		return team, nil
+	}
+
+	existing, err := s.repo.GetTeamByName(ctx, teamName)
+	if err == nil && existing != nil {
+		return nil, errors.New("team name is already taken")
+	}
+
+	team.TeamName = teamName
+	if err := s.repo.UpdateTeam(ctx, team); err != nil {
+		return nil, err
+	}
+
+	if s.producer != nil {
+		err := s.producer.SendTeamEvent(
+			kafka.EventTeamUpdated,
+			teamID,
+			currentUserID,
+			uuid.Nil,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for team update: %v", err)
+		}
+	}
+
+	return team, nil
+}
+
+// SetTeamArchived archives or restores a team (only MAIN_MANAGER).
+// Archived teams are read-only for assets.
+func (s *TeamService) SetTeamArchived(ctx context.Context, teamID uuid.UUID, archived bool, currentUserID uuid.UUID) (*models.Team, error) {
+	currentUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, currentUserID)
+	if err != nil || currentUserRole != "MAIN_MANAGER" {
+		return nil, errors.New("you are not the main manager")
+	}
+
+	team, err := s.repo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team.Archived == archived {
+		return team, nil
+	}
+
+	team.Archived = archived
+	if err := s.repo.UpdateTeam(ctx, team); err != nil {
+		return nil, err
+	}
+
+	if s.producer != nil {
+		err := s.producer.SendTeamEvent(
+			kafka.EventTeamUpdated,
+			teamID,
+			currentUserID,
+			uuid.Nil,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for team update: %v", err)
+		}
+	}
+
+	return team, nil
+}
+
+// DeleteTeam removes a team and everything it owns (only MAIN_MANAGER)
+func (s *TeamService) DeleteTeam(ctx context.Context, teamID uuid.UUID, currentUserID uuid.UUID) error {
+	currentUserRole, err := s.repo.GetUserRoleInTeam(ctx, teamID, currentUserID)
+	if err != nil || currentUserRole != "MAIN_MANAGER" {
+		return errors.New("you are not the main manager")
+	}
+
+	if err := s.repo.DeleteTeam(ctx, teamID); err != nil {
+		return err
+	}
+
+	if s.producer != nil {
+		err := s.producer.SendTeamEvent(
+			kafka.EventTeamDeleted,
+			teamID,
+			currentUserID,
+			uuid.Nil,
+		)
+		if err != nil {
+			log.Printf("Failed to send Kafka event for team deletion: %v", err)
 		}
 	}
 
-	return s.repo.RemoveMemberFromTeam(ctx, teamID, managerID)
+	return nil
 }
@@ -0,0 +1,15 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// generateToken returns n random bytes hex-encoded, for invitation and public link tokens
+func generateToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
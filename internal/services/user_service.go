@@ -15,6 +15,7 @@ import (
 // IUserService định nghĩa các phương thức cần có
 type IUserService interface {
 	GetUserByID(ctx context.Context, userID uuid.UUID) (*models.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUsersByIDs(ctx context.Context, userIDs []uuid.UUID) ([]models.User, error)
 	UserExists(ctx context.Context, userID uuid.UUID) (bool, error)
 	CreateUser(ctx context.Context, username, email, password, role string) (*models.User, error)
@@ -74,14 +75,54 @@ func (s *UserService) GetUserByID(ctx context.Context, userID uuid.UUID) (*model
 	}
 
 	// Check if user was found
-	if response.User == nil && response.User.ID == uuid.Nil {
-		log.Printf("User not found with ID: %s %s", userIDStr, response)
+	if response.User == nil || response.User.ID == uuid.Nil {
+		log.Printf("User not found with ID: %s", userIDStr)
 		return nil, fmt.Errorf("user not found with ID: %s", userIDStr)
 	}
 
 	return response.User, nil
 }
 
+// GetUserByEmail fetches a user by their email address
+func (s *UserService) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
+	log.Printf("Fetching user with email: %s", email)
+
+	// Create GraphQL request
+	req := graphql.NewRequest(`
+        query GetUserByEmail($email: String!) {
+            userByEmail(email: $email) {
+                userId
+                username
+                email
+                role
+            }
+        }
+    `)
+
+	// Set variables
+	req.Var("email", email)
+
+	// Add timeout to context if not already set
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Execute request
+	var response struct {
+		User *models.User `json:"userByEmail"`
+	}
+
+	if err := s.client.Run(ctx, req, &response); err != nil {
+		log.Printf("GraphQL request failed: %v", err)
+		return nil, fmt.Errorf("failed to fetch user: %w", err)
+	}
+
+	if response.User == nil || response.User.ID == uuid.Nil {
+		return nil, fmt.Errorf("user not found with email: %s", email)
+	}
+
+	return response.User, nil
+}
+
 // GetUsersByIDs fetches multiple users by their IDs
 func (s *UserService) GetUsersByIDs(ctx context.Context, userIDs []uuid.UUID) ([]models.User, error) {
 	if len(userIDs) == 0 {
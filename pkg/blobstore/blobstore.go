@@ -0,0 +1,41 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotFound is returned when a key does not exist in the store
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps opaque blobs under string keys
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFromEnv builds the store selected by BLOB_STORE ("local" by default, or "s3")
+func NewFromEnv() (Store, error) {
+	switch os.Getenv("BLOB_STORE") {
+	case "", "local":
+		dir := os.Getenv("BLOB_LOCAL_DIR")
+		if dir == "" {
+			dir = "./data/blobs"
+		}
+		return NewLocalStore(dir), nil
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q", os.Getenv("BLOB_STORE"))
+	}
+}
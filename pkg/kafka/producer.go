@@ -12,20 +12,69 @@ import (
 
 // TeamEvent represents a team activity event
 type TeamEvent struct {
-	EventType    string    `json:"eventType"`
-	TeamID       uuid.UUID `json:"teamId"`
-	PerformedBy  uuid.UUID `json:"performedBy"`
-	TargetUserID uuid.UUID `json:"targetUserId,omitempty"`
-	Timestamp    string    `json:"timestamp"`
+	EventType    string       `json:"eventType"`
+	TeamID       uuid.UUID    `json:"teamId"`
+	PerformedBy  uuid.UUID    `json:"performedBy"`
+	TargetUserID uuid.UUID    `json:"targetUserId,omitempty"`
+	Offboarding  *Offboarding `json:"offboarding,omitempty"` // set on MEMBER_REMOVED
+	Timestamp    string       `json:"timestamp"`
+}
+
+// Offboarding tells what happened to a removed member's folders, notes and shares
+type Offboarding struct {
+	ReassignedTo      *uuid.UUID     `json:"reassignedTo,omitempty"`
+	ReassignedFolders []uuid.UUID    `json:"reassignedFolders"`
+	ReassignedNotes   []uuid.UUID    `json:"reassignedNotes"`
+	RevokedShares     []RevokedShare `json:"revokedShares"`
+}
+
+// RevokedShare is a share deleted while offboarding a member
+type RevokedShare struct {
+	ShareID      uuid.UUID `json:"shareId"`
+	ResourceID   uuid.UUID `json:"resourceId"`
+	ResourceType string    `json:"resourceType"`
+	Permission   string    `json:"permission"`
+}
+
+// ShareEvent represents a change to a folder or note share
+type ShareEvent struct {
+	EventType     string    `json:"eventType"`
+	TeamID        uuid.UUID `json:"teamId"`
+	ResourceID    uuid.UUID `json:"resourceId"`
+	ResourceType  string    `json:"resourceType"`
+	OwnerID       uuid.UUID `json:"ownerId"`
+	PrincipalType string    `json:"principalType"` // "user", "team" or "group"
+	PrincipalID   uuid.UUID `json:"principalId"`
+	Permission    string    `json:"permission"`
+	Timestamp     string    `json:"timestamp"`
+}
+
+// CommentEvent represents a new comment on a note or a user mentioned in one
+type CommentEvent struct {
+	EventType       string     `json:"eventType"`
+	TeamID          uuid.UUID  `json:"teamId"`
+	NoteID          uuid.UUID  `json:"noteId"`
+	CommentID       uuid.UUID  `json:"commentId"`
+	ParentID        *uuid.UUID `json:"parentId,omitempty"`
+	AuthorID        uuid.UUID  `json:"authorId"`
+	MentionedUserID uuid.UUID  `json:"mentionedUserId,omitempty"` // set on COMMENT_MENTION
+	Timestamp       string     `json:"timestamp"`
 }
 
 // EventType constants
 const (
 	EventTeamCreated    = "TEAM_CREATED"
+	EventTeamUpdated    = "TEAM_UPDATED"
+	EventTeamDeleted    = "TEAM_DELETED"
+	EventMemberInvited  = "MEMBER_INVITED"
 	EventMemberAdded    = "MEMBER_ADDED"
 	EventMemberRemoved  = "MEMBER_REMOVED"
 	EventManagerAdded   = "MANAGER_ADDED"
 	EventManagerRemoved = "MANAGER_REMOVED"
+	EventOwnerChanged   = "OWNER_CHANGED"
+	EventShareExpired   = "SHARE_EXPIRED"
+	EventCommentCreated = "COMMENT_CREATED"
+	EventCommentMention = "COMMENT_MENTION"
 )
 
 // Producer encapsulates a Kafka producer
@@ -83,6 +132,37 @@ func (p *Producer) SendTeamEvent(eventType string, teamID uuid.UUID, performedBy
 		Timestamp:    time.Now().UTC().Format(time.RFC3339),
 	}
 
+	return p.send(teamID, event)
+}
+
+// SendMemberRemovedEvent sends a MEMBER_REMOVED event carrying the offboarding outcome
+func (p *Producer) SendMemberRemovedEvent(teamID uuid.UUID, performedBy, targetUserID uuid.UUID, offboarding *Offboarding) error {
+	event := TeamEvent{
+		EventType:    EventMemberRemoved,
+		TeamID:       teamID,
+		PerformedBy:  performedBy,
+		TargetUserID: targetUserID,
+		Offboarding:  offboarding,
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+	}
+
+	return p.send(teamID, event)
+}
+
+// SendShareEvent sends a share event to the Kafka topic
+func (p *Producer) SendShareEvent(event ShareEvent) error {
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	return p.send(event.TeamID, event)
+}
+
+// SendCommentEvent sends a comment event to the Kafka topic
+func (p *Producer) SendCommentEvent(event CommentEvent) error {
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	return p.send(event.TeamID, event)
+}
+
+// send publishes an event keyed by its team so a team's events stay ordered
+func (p *Producer) send(teamID uuid.UUID, event interface{}) error {
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %w", err)
@@ -0,0 +1,188 @@
+package redisclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Change types pushed to folder and note streams
+const (
+	ChangeFolderCreated  = "folder.created"
+	ChangeFolderUpdated  = "folder.updated"
+	ChangeFolderMoved    = "folder.moved"
+	ChangeFolderDeleted  = "folder.deleted"
+	ChangeFolderRestored = "folder.restored"
+	ChangeNoteCreated    = "note.created"
+	ChangeNoteUpdated    = "note.updated"
+	ChangeNoteMoved      = "note.moved"
+	ChangeNoteDeleted    = "note.deleted"
+	ChangeNoteRestored   = "note.restored"
+	ChangeShareChanged   = "share.changed"
+	ChangeCommentCreated = "comment.created"
+)
+
+// Change is a real-time notification about a folder or note. It only identifies what changed;
+// subscribers fetch the resource again to see the new state.
+type Change struct {
+	Type         string     `json:"type"`
+	ResourceType string     `json:"resourceType"` // "folder" or "note"
+	ResourceID   uuid.UUID  `json:"resourceId"`
+	FolderID     *uuid.UUID `json:"folderId,omitempty"` // folder of a note, parent of a folder
+	ActorID      uuid.UUID  `json:"actorId"`            // zero for changes made by background jobs
+	Version      int        `json:"version,omitempty"`
+	CommentID    *uuid.UUID `json:"commentId,omitempty"`
+	Timestamp    time.Time  `json:"timestamp"`
+}
+
+// changeBuffer is how many changes a slow subscriber may lag behind before changes are dropped for it
+const changeBuffer = 32
+
+// ChangeFeed fans folder and note changes out to the subscribers on every server instance through
+// Redis pub/sub. Each instance holds one pub/sub connection and only subscribes to the channels its
+// local subscribers watch.
+type ChangeFeed struct {
+	client *redis.Client
+
+	// mu guards the local subscribers and is never held across a Redis round-trip
+	mu          sync.Mutex
+	pubsub      *redis.PubSub
+	subscribers map[string]map[chan Change]struct{}
+
+	// subMu serializes SUBSCRIBE and UNSUBSCRIBE and guards the channels the connection listens to
+	subMu      sync.Mutex
+	subscribed map[string]bool
+}
+
+func NewChangeFeed(client *redis.Client) *ChangeFeed {
+	return &ChangeFeed{
+		client:      client,
+		subscribers: make(map[string]map[chan Change]struct{}),
+		subscribed:  make(map[string]bool),
+	}
+}
+
+func FolderChannel(folderID uuid.UUID) string {
+	return fmt.Sprintf("folder:%s:changes", folderID.String())
+}
+
+func NoteChannel(noteID uuid.UUID) string {
+	return fmt.Sprintf("note:%s:changes", noteID.String())
+}
+
+// Publish sends a change to the given channels
+func (f *ChangeFeed) Publish(ctx context.Context, change Change, channels ...string) error {
+	if f.client == nil {
+		return fmt.Errorf("Redis client not initialized")
+	}
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now().UTC()
+	}
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		if err := f.client.Publish(ctx, channel, payload).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Subscribe delivers the changes published to channel until the returned cancel function is called
+func (f *ChangeFeed) Subscribe(ctx context.Context, channel string) (<-chan Change, func(), error) {
+	if f.client == nil {
+		return nil, nil, fmt.Errorf("Redis client not initialized")
+	}
+
+	changes := make(chan Change, changeBuffer)
+	f.mu.Lock()
+	if f.pubsub == nil {
+		// The shared connection lives as long as the process, so it must not inherit a request context
+		f.pubsub = f.client.Subscribe(context.Background())
+		go f.dispatch(f.pubsub.Channel())
+	}
+	if f.subscribers[channel] == nil {
+		f.subscribers[channel] = make(map[chan Change]struct{})
+	}
+	f.subscribers[channel][changes] = struct{}{}
+	f.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() { f.unsubscribe(channel, changes) })
+	}
+	if err := f.syncChannel(ctx, channel); err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return changes, cancel, nil
+}
+
+func (f *ChangeFeed) unsubscribe(channel string, changes chan Change) {
+	f.mu.Lock()
+	delete(f.subscribers[channel], changes)
+	close(changes)
+	if len(f.subscribers[channel]) == 0 {
+		delete(f.subscribers, channel)
+	}
+	f.mu.Unlock()
+
+	if err := f.syncChannel(context.Background(), channel); err != nil {
+		log.Printf("Failed to update the subscription to %s: %v", channel, err)
+	}
+}
+
+// syncChannel subscribes the connection to channel while it has local subscribers and unsubscribes it
+// once the last one leaves. Concurrent calls for the same channel settle on the latest state.
+func (f *ChangeFeed) syncChannel(ctx context.Context, channel string) error {
+	f.subMu.Lock()
+	defer f.subMu.Unlock()
+
+	f.mu.Lock()
+	wanted := len(f.subscribers[channel]) > 0
+	f.mu.Unlock()
+
+	switch {
+	case wanted && !f.subscribed[channel]:
+		if err := f.pubsub.Subscribe(ctx, channel); err != nil {
+			return err
+		}
+		f.subscribed[channel] = true
+	case !wanted && f.subscribed[channel]:
+		delete(f.subscribed, channel)
+		if err := f.pubsub.Unsubscribe(context.Background(), channel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dispatch hands every message to the local subscribers of its channel. go-redis reconnects and
+// resubscribes on its own, so this runs for the lifetime of the process.
+func (f *ChangeFeed) dispatch(messages <-chan *redis.Message) {
+	for message := range messages {
+		var change Change
+		if err := json.Unmarshal([]byte(message.Payload), &change); err != nil {
+			log.Printf("Invalid change on %s: %v", message.Channel, err)
+			continue
+		}
+
+		f.mu.Lock()
+		for changes := range f.subscribers[message.Channel] {
+			select {
+			case changes <- change:
+			default:
+				log.Printf("Dropped change %s for a slow subscriber of %s", change.Type, message.Channel)
+			}
+		}
+		f.mu.Unlock()
+	}
+}
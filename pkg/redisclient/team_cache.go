@@ -116,6 +116,15 @@ func (tc *TeamCache) RemoveMember(ctx context.Context, teamID uuid.UUID, userID
 	return tc.client.SRem(ctx, key, userID.String()).Err()
 }
 
+// DeleteTeam drops the cached members of a team
+func (tc *TeamCache) DeleteTeam(ctx context.Context, teamID uuid.UUID) error {
+	if tc.client == nil {
+		return fmt.Errorf("redis client not initialized")
+	}
+
+	return tc.client.Del(ctx, tc.GetTeamMembersKey(teamID)).Err()
+}
+
 // SMembers is a wrapper around the Redis SMembers command
 func (tc *TeamCache) SMembers(ctx context.Context, key string) *redis.StringSliceCmd {
 	return tc.client.SMembers(ctx, key)
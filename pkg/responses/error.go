@@ -14,3 +14,16 @@ func Error(c *gin.Context, status int, err error, message string) {
 
 	c.JSON(status, ErrorResponse{Data: errorRes})
 }
+
+// ErrorWithFields is Error with extra machine-readable fields next to the message
+func ErrorWithFields(c *gin.Context, status int, err error, message string, fields map[string]interface{}) {
+	errorRes := map[string]interface{}{
+		"message": message,
+		"error":   err.Error(),
+	}
+	for key, value := range fields {
+		errorRes[key] = value
+	}
+
+	c.JSON(status, ErrorResponse{Data: errorRes})
+}
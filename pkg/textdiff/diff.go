@@ -0,0 +1,109 @@
+package textdiff
+
+import "strings"
+
+// Line operations
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// Inputs whose differing middle section exceeds this many cells are not
+// aligned line by line; the section is reported as deleted then inserted.
+const maxTableCells = 4_000_000
+
+// Line is one line of a line-based diff
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// Lines computes a line diff turning a into b using a longest common subsequence.
+func Lines(a, b string) []Line {
+	return diff(splitLines(a), splitLines(b))
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+}
+
+func diff(a, b []string) []Line {
+	// Trim the common prefix and suffix so the LCS table only covers the changed part
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]Line, 0, len(a)+len(b))
+	for _, text := range a[:prefix] {
+		result = append(result, Line{Op: OpEqual, Text: text})
+	}
+	result = append(result, middle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		result = append(result, Line{Op: OpEqual, Text: text})
+	}
+	return result
+}
+
+func middle(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n*m > maxTableCells {
+		result := make([]Line, 0, n+m)
+		for _, text := range a {
+			result = append(result, Line{Op: OpDelete, Text: text})
+		}
+		for _, text := range b {
+			result = append(result, Line{Op: OpInsert, Text: text})
+		}
+		return result
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int32, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	result := make([]Line, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			result = append(result, Line{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			result = append(result, Line{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			result = append(result, Line{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		result = append(result, Line{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < m; j++ {
+		result = append(result, Line{Op: OpInsert, Text: b[j]})
+	}
+	return result
+}